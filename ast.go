@@ -4,6 +4,14 @@
  email: soforth@qq.com
  description: a HTTP package filter, supported BNF as bellow:
 
+ rule -> decl rule                  // declarations come first
+		| grammer
+		;
+ decl -> let VAR_str = factor       // bind a value, evaluated once when first used
+		| def VAR_str = expr        // bind a condition, evaluated once when first used
+		| import STRING_const [as VAR_str] // declarations of a file as namespace.name
+		| include STRING_const      // declarations of a file as they are
+		;
  grammer -> expr => RET grammer     // if ( expr ) return RET else return grammer
 		| default => RET grammer    // if grammer != 0 return grammer default: return RET
 		| expr grammer              // if ( expr) return grammer else return 0
@@ -22,15 +30,28 @@
 		| factor <= factor          // left less than or equal right
 		| factor # REGEX            // left regex match right pattern
 		| factor !# REGEX           // left regex not match right pattern
+		| factor #i REGEX           // left regex match right pattern, ignoring case
+		| factor !#i REGEX          // left regex not match right pattern, ignoring case
+		| factor ~ GLOB             // left match right wildcard pattern
+		| factor !~ GLOB            // left not match right wildcard pattern
 		| ( expr )                  // term can be a expr in paren
+		| any ( factor, expr )      // expr holds for some item of the array, 'it' is the item
+		| all ( factor, expr )      // expr holds for every item of the array
+		| none ( factor, expr )     // expr holds for no item of the array
+		| func                      // function result is not zero
+		| VAR_str                   // value of a let or def is not zero
 		;
  list -> factor list;               // list is recursive defined
  factor -> DOUBLE_const             // factor can be double immediate constant
+		| INTEGER_const             // or integer immediate constant (123, -7, 0xff)
 		| STRING_const              // also can be string immediate constant
 		| VAR_str                   // also can be variable as symbol input by user
+		| $VAR_str                  // also can be parameter bound by host
 		| func                      // also can be a internal function
 		;
- func -> VAR_str ( list );          // function has zero or more arguments
+ func -> VAR_str ( list )           // function has zero or more arguments
+		| VAR_str ( factor, ( list ) [, list] ) // function taking a list literal
+		;
 *************************************************************************************/
 package filter
 
@@ -38,10 +59,8 @@ import (
 	"crypto/md5"
 	"errors"
 	"fmt"
-	"io"
-	"math"
 	"regexp"
-	"strconv"
+	"unicode/utf8"
 )
 
 type GKind_t int  // for Grammer
@@ -70,17 +89,56 @@ const (
 	MA   = TKind_t(8)
 	NM   = TKind_t(9)
 	EXPR = TKind_t(10)
+	BOOL = TKind_t(11)
+	MAI  = TKind_t(12)
+	NMI  = TKind_t(13)
+	GL   = TKind_t(14)
+	NG   = TKind_t(15)
+	ANY  = TKind_t(16)
+	ALL  = TKind_t(17)
+	NONE = TKind_t(18)
 
 	DOUBLE   = FKind_t(0)
 	STRING   = FKind_t(1)
 	VARIABLE = FKind_t(2)
 	FUNCTION = FKind_t(3)
-
-	LEN   = FnKind_t(0)
-	MD5   = FnKind_t(1)
-	COUNT = FnKind_t(2)
-	ATOI  = FnKind_t(3)
-	ITOA  = FnKind_t(4)
+	LIST     = FKind_t(4)
+	ARRAY    = FKind_t(5)
+	INTEGER  = FKind_t(6)
+
+	LEN            = FnKind_t(0)
+	MD5            = FnKind_t(1)
+	COUNT          = FnKind_t(2)
+	ATOI           = FnKind_t(3)
+	ITOA           = FnKind_t(4)
+	SHA1           = FnKind_t(5)
+	SHA256         = FnKind_t(6)
+	SHA512         = FnKind_t(7)
+	HMAC_SHA256    = FnKind_t(8)
+	CRC32          = FnKind_t(9)
+	BASE64_ENCODE  = FnKind_t(10)
+	BASE64_DECODE  = FnKind_t(11)
+	HEX_ENCODE     = FnKind_t(12)
+	HEX_DECODE     = FnKind_t(13)
+	URL_ENCODE     = FnKind_t(14)
+	URL_DECODE     = FnKind_t(15)
+	SECURE_EQ      = FnKind_t(16)
+	NOW            = FnKind_t(17)
+	UNIX           = FnKind_t(18)
+	PARSE_TIME     = FnKind_t(19)
+	HOUR           = FnKind_t(20)
+	WEEKDAY        = FnKind_t(21)
+	DURATION       = FnKind_t(22)
+	SINCE          = FnKind_t(23)
+	UNTIL          = FnKind_t(24)
+	RATE           = FnKind_t(25)
+	COUNT_DISTINCT = FnKind_t(26)
+	MATCH          = FnKind_t(27)
+	CONTAINS_ANY   = FnKind_t(28)
+	SIZE           = FnKind_t(29)
+	BYTE_LEN       = FnKind_t(30)
+	TIME_ADD       = FnKind_t(31)
+	TIME_SUB       = FnKind_t(32)
 )
 
 type Grammer struct {
@@ -97,19 +155,22 @@ type Expr struct {
 }
 
 type Term struct {
-	Kind  TKind_t // IN, NI, GT, LT, EQ, NE, GE, LE, MA, NM, EXPR
+	Kind  TKind_t // IN, NI, GT, LT, EQ, NE, GE, LE, MA, NM, MAI, NMI, GL, NG, ANY, ALL, NONE, EXPR, BOOL
 	Left  *Factor
 	Right interface{}
 }
 
 type Factor struct {
-	Kind  FKind_t // DOUBLE, STRING, VARIABLE, FUNCTION
+	Kind  FKind_t // DOUBLE, INTEGER, STRING, VARIABLE, FUNCTION, LIST, ARRAY
 	Value interface{}
 }
 
 type Func struct {
-	Kind FnKind_t // LEN, MD5, COUNT, ATOI, ITOA
-	List *List
+	Kind    FnKind_t // LEN, MD5, COUNT, ATOI, ITOA, SHA1, ...
+	List    *List
+	Regex   *regexp.Regexp // MATCH: pattern compiled at parse time
+	Group   int            // MATCH: capture group to return
+	Matcher *Matcher       // CONTAINS_ANY: keywords compiled at parse time
 }
 
 type List struct {
@@ -118,7 +179,7 @@ type List struct {
 }
 
 type SymList struct {
-	Kind  FKind_t // DOUBLE, STRING
+	Kind  FKind_t // DOUBLE, INTEGER, STRING, ARRAY
 	Name  string
 	Value interface{}
 	Next  *SymList
@@ -170,6 +231,22 @@ func tkind2str(kind TKind_t) string {
 		return "#"
 	case NM:
 		return "!#"
+	case MAI:
+		return "#i"
+	case NMI:
+		return "!#i"
+	case GL:
+		return "~"
+	case NG:
+		return "!~"
+	case ANY:
+		return "any"
+	case ALL:
+		return "all"
+	case NONE:
+		return "none"
+	case BOOL:
+		return "<func alone>"
 	}
 	return fmt.Sprintf("%d", int(kind))
 }
@@ -178,12 +255,18 @@ func fkind2str(kind FKind_t) string {
 	switch kind {
 	case DOUBLE:
 		return "float64"
+	case INTEGER:
+		return "int64"
 	case STRING:
 		return "string"
 	case VARIABLE:
 		return "var"
 	case FUNCTION:
 		return "func"
+	case LIST:
+		return "list"
+	case ARRAY:
+		return "array"
 	}
 	return fmt.Sprintf("%d", int(kind))
 }
@@ -200,6 +283,62 @@ func fnkind2str(kind FnKind_t) string {
 		return "itoa"
 	case ATOI:
 		return "atoi"
+	case SHA1:
+		return "sha1"
+	case SHA256:
+		return "sha256"
+	case SHA512:
+		return "sha512"
+	case HMAC_SHA256:
+		return "hmac_sha256"
+	case CRC32:
+		return "crc32"
+	case BASE64_ENCODE:
+		return "base64_encode"
+	case BASE64_DECODE:
+		return "base64_decode"
+	case HEX_ENCODE:
+		return "hex_encode"
+	case HEX_DECODE:
+		return "hex_decode"
+	case URL_ENCODE:
+		return "url_encode"
+	case URL_DECODE:
+		return "url_decode"
+	case SECURE_EQ:
+		return "secure_eq"
+	case NOW:
+		return "now"
+	case UNIX:
+		return "unix"
+	case PARSE_TIME:
+		return "parse_time"
+	case HOUR:
+		return "hour"
+	case WEEKDAY:
+		return "weekday"
+	case DURATION:
+		return "duration"
+	case SINCE:
+		return "since"
+	case UNTIL:
+		return "until"
+	case RATE:
+		return "rate"
+	case COUNT_DISTINCT:
+		return "count_distinct"
+	case MATCH:
+		return "match"
+	case CONTAINS_ANY:
+		return "contains_any"
+	case SIZE:
+		return "size"
+	case BYTE_LEN:
+		return "byte_len"
+	case TIME_ADD:
+		return "time_add"
+	case TIME_SUB:
+		return "time_sub"
 	}
 	return fmt.Sprintf("%d", int(kind))
 }
@@ -259,18 +398,29 @@ func NewTerm(kind TKind_t, lfactor *Factor, list *List, rfactor *Factor, expr *E
 	case GT, LT, EQ, NE, GE, LE:
 		t.Left = lfactor
 		t.Right = rfactor
-	case MA, NM:
+	case MA, NM, MAI, NMI:
+		// constant patterns are compiled by the parser, see checkTerm()
 		t.Left = lfactor
+		t.Right = rfactor
+	case GL, NG:
+		t.Left = lfactor
+		if rfactor == nil || rfactor.Kind != STRING {
+			return t, errors.New("glob pattern should be a 'string' constant")
+		}
 		if v, err := cast2string(rfactor.Value); err != nil {
 			return t, err
-		} else {
-			if t.Right, err = regexp.CompilePOSIX(v); err != nil {
-				return t, err
-			}
+		} else if t.Right, err = CompileGlob(v); err != nil {
+			return t, err
 		}
+	case ANY, ALL, NONE:
+		t.Left = lfactor
+		t.Right = expr
 	case EXPR:
 		t.Left = nil
 		t.Right = expr
+	case BOOL:
+		t.Left = lfactor
+		t.Right = nil
 	}
 	return t, nil
 }
@@ -295,9 +445,41 @@ func NewFunc(kind FnKind_t, list *List) (*Func, error) {
 	fn := new(Func)
 	fn.Kind = kind
 	fn.List = list
+	switch kind {
+	case MATCH:
+		return fn, checkMatch(fn)
+	case CONTAINS_ANY:
+		return fn, CompileContainsAny(fn)
+	}
+	for p := list; p != nil; p = p.Next {
+		if p.Factor.Kind == LIST {
+			return fn, errors.New(fmt.Sprintf("%s() doesn't take a list", fnkind2str(kind)))
+		}
+	}
 	return fn, nil
 }
 
+/*
+ * value of an array symbol
+ */
+func NewArrayFactor(items []*SymList) (*Factor, error) {
+	f := new(Factor)
+	f.Kind = ARRAY
+	f.Value = items
+	return f, nil
+}
+
+/*
+ * a list literal given as a function argument, e.g. the keywords of
+ * contains_any()
+ */
+func NewListFactor(list *List) (*Factor, error) {
+	f := new(Factor)
+	f.Kind = LIST
+	f.Value = list
+	return f, nil
+}
+
 func NewList(factor *Factor, next *List) (*List, error) {
 	l := new(List)
 	l.Factor = factor
@@ -305,25 +487,28 @@ func NewList(factor *Factor, next *List) (*List, error) {
 	return l, nil
 }
 
-func EvalGrammer(grammer *Grammer, symlist *SymList) (int, error) {
+func EvalGrammer(grammer *Grammer, env *Env) (int, error) {
 	var err error
 	ret := -1
 	if grammer == nil {
 		return 0, nil
 	}
+	if err = env.step(); err != nil {
+		return -1, err
+	}
 
 	switch grammer.Kind {
 	case EGET:
-		ret, err = EvalExpr(grammer.Expr, symlist)
+		ret, err = EvalExpr(grammer.Expr, env)
 		if err != nil {
 			return -1, err
 		}
 		if ret == 1 {
 			return int(grammer.Ret), nil
 		}
-		return EvalGrammer(grammer.Grammer, symlist)
+		return EvalGrammer(grammer.Grammer, env)
 	case DGET:
-		ret, err = EvalGrammer(grammer.Grammer, symlist)
+		ret, err = EvalGrammer(grammer.Grammer, env)
 		if err != nil {
 			return -1, err
 		}
@@ -332,83 +517,140 @@ func EvalGrammer(grammer *Grammer, symlist *SymList) (int, error) {
 		}
 		return ret, nil
 	case EEXPR:
-		ret, err = EvalExpr(grammer.Expr, symlist)
+		ret, err = EvalExpr(grammer.Expr, env)
 		if err != nil {
 			return -1, err
 		}
 		if ret != 0 {
 			return ret, nil
 		}
-		return EvalGrammer(grammer.Grammer, symlist)
+		return EvalGrammer(grammer.Grammer, env)
 	}
 
 	return -1, errors.New(fmt.Sprintf("grammer operator '%s' not supported", gkind2str(grammer.Kind)))
 }
 
-func EvalExpr(expr *Expr, symlist *SymList) (int, error) {
+func EvalExpr(expr *Expr, env *Env) (int, error) {
 	var err error
 	var left int
 	if expr == nil {
 		return -1, errors.New("expr with invalid parameter")
 	}
+	if err = env.enter(); err != nil {
+		return -1, err
+	}
+	defer env.leave()
 
 	switch expr.Kind {
 	case AND:
-		if left, err = EvalExpr(expr.Left, symlist); err != nil {
+		if left, err = EvalExpr(expr.Left, env); err != nil {
 			return -1, err
 		}
 		if left <= 0 {
 			return left, nil
 		}
-		return EvalTerm((*Term)(expr.Right), symlist)
+		return EvalTerm((*Term)(expr.Right), env)
 	case OR:
-		if left, err = EvalExpr(expr.Left, symlist); err != nil {
+		if left, err = EvalExpr(expr.Left, env); err != nil {
 			return -1, err
 		}
 		if left != 0 {
 			return left, nil
 		}
-		return EvalTerm((*Term)(expr.Right), symlist)
+		return EvalTerm((*Term)(expr.Right), env)
 	case TERM:
-		return EvalTerm((*Term)(expr.Right), symlist)
+		return EvalTerm((*Term)(expr.Right), env)
 	}
 	return -1, errors.New(fmt.Sprintf("expr operator '%s' not supported", ekind2str(expr.Kind)))
 }
 
-func EvalTerm(term *Term, symlist *SymList) (int, error) {
+func EvalTerm(term *Term, env *Env) (int, error) {
 	if term == nil {
 		return -1, errors.New("term with invalid parameter")
 	}
+	if err := env.enter(); err != nil {
+		return -1, err
+	}
+	defer env.leave()
 
 	switch term.Kind {
 	case IN, NI:
 		switch v := term.Right.(type) {
 		case *List:
-			return EvalList(term.Kind, term.Left, v, symlist)
+			return EvalList(term.Kind, term.Left, v, env)
 		}
 	case GT, LT, EQ, NE, GE, LE:
 		switch v := term.Right.(type) {
 		case *Factor:
-			return EvalCmp(term.Kind, term.Left, v, symlist)
+			return EvalCmp(term.Kind, term.Left, v, env)
 		}
-	case MA, NM:
+	case MA, NM, MAI, NMI:
 		switch v := term.Right.(type) {
 		case *regexp.Regexp:
-			return EvalRegex(term.Kind, term.Left, v, symlist)
+			return EvalRegex(term.Kind, term.Left, v, env)
+		case *Factor:
+			if pattern, err := EvalConcat(tkind2str(term.Kind), &List{Factor: v}, env); err != nil {
+				return -1, err
+			} else if regex, err := env.regex(pattern, foldkind(term.Kind)); err != nil {
+				return -1, err
+			} else {
+				return EvalRegex(term.Kind, term.Left, regex, env)
+			}
+		}
+	case GL, NG:
+		switch v := term.Right.(type) {
+		case *regexp.Regexp:
+			return EvalRegex(term.Kind, term.Left, v, env)
+		}
+	case ANY, ALL, NONE:
+		switch v := term.Right.(type) {
+		case *Expr:
+			return EvalQuant(term.Kind, term.Left, v, env)
 		}
 	case EXPR:
 		switch v := term.Right.(type) {
 		case *Expr:
-			return EvalExpr(v, symlist)
+			return EvalExpr(v, env)
 		}
+	case BOOL:
+		return EvalBool(term.Left, env)
 	}
 	return -1, errors.New(fmt.Sprintf("term with invalid kind '%s'", tkind2str(term.Kind)))
 }
 
-func EvalList(kind TKind_t, factor *Factor, list *List, symlist *SymList) (int, error) {
+func EvalBool(factor *Factor, env *Env) (int, error) {
+	if factor == nil {
+		return -1, errors.New("bool with invalid parameter")
+	}
+
+	value, err := EvalFactor(factor, env)
+	if err != nil {
+		return -1, err
+	}
+	switch value.Kind {
+	case DOUBLE, INTEGER:
+		if v, err := cast2number(value); err != nil {
+			return -1, err
+		} else {
+			return bool2int(v != 0), nil
+		}
+	case STRING:
+		if v, err := cast2string(value.Value); err != nil {
+			return -1, err
+		} else {
+			return bool2int(v != ""), nil
+		}
+	}
+	return -1, errors.New(fmt.Sprintf("bool with invalid kind '%s'", fkind2str(value.Kind)))
+}
+
+func EvalList(kind TKind_t, factor *Factor, list *List, env *Env) (int, error) {
 	found := false
 	for p := list; p != nil; p = p.Next {
-		if rc, err := EvalCmp(EQ, factor, p.Factor, symlist); err != nil {
+		if err := env.step(); err != nil {
+			return -1, err
+		}
+		if rc, err := EvalCmp(EQ, factor, p.Factor, env); err != nil {
 			return -1, err
 		} else if rc > 0 {
 			found = true
@@ -421,7 +663,7 @@ func EvalList(kind TKind_t, factor *Factor, list *List, symlist *SymList) (int,
 	return bool2int(!found), nil
 }
 
-func EvalRegex(kind TKind_t, lfactor *Factor, regex *regexp.Regexp, symlist *SymList) (int, error) {
+func EvalRegex(kind TKind_t, lfactor *Factor, regex *regexp.Regexp, env *Env) (int, error) {
 	if lfactor == nil || regex == nil {
 		return -1, errors.New("regexp with invalid parameter")
 	}
@@ -431,7 +673,7 @@ func EvalRegex(kind TKind_t, lfactor *Factor, regex *regexp.Regexp, symlist *Sym
 	if lfactor.Kind == VARIABLE {
 		if v, err := cast2string(lfactor.Value); err != nil {
 			return -1, err
-		} else if lv, err = SymbolLookup(symlist, v); err != nil {
+		} else if lv, err = env.Lookup(v); err != nil {
 			return -1, err
 		}
 	} else if lfactor.Kind == FUNCTION {
@@ -439,32 +681,41 @@ func EvalRegex(kind TKind_t, lfactor *Factor, regex *regexp.Regexp, symlist *Sym
 			return -1, err
 		} else {
 			var value *Factor
-			value, err := EvalFunc(v, symlist)
+			value, err := EvalFunc(v, env)
 			if err != nil {
 				return -1, err
 			}
-			if value.Kind != STRING {
-				return -1, errors.New("func ret should be 'string'")
-			}
 			lv = value
 		}
 	}
 
-	if lv.Kind != STRING {
+	if lv.Kind != STRING && !isNumber(lv) {
 		return -1, err
 	}
 
-	if v, err := cast2string(lv.Value); err == nil {
-		rc := regex.MatchString(v)
-		return bool2int((kind == MA && rc == true) ||
-			(kind == NM && rc == false)), nil
+	if v, err := env.asString("regex match", lv); err == nil {
+		rc := regex.MatchString(env.normalize(v))
+		return bool2int(((kind == MA || kind == MAI || kind == GL) && rc == true) ||
+			((kind == NM || kind == NMI || kind == NG) && rc == false)), nil
 	}
 	return -1, errors.New("left value has invalid type")
 }
 
-func EvalLen(list *List, symlist *SymList) (*Factor, error) {
+/*
+ * len(x): characters (runes) of a string, a byte that is no valid UTF-8
+ * counts as one; byte_len(x): bytes of a string
+ */
+func EvalLen(list *List, env *Env) (*Factor, error) {
+	return evalLen("len", list, env, utf8.RuneCountInString)
+}
+
+func EvalByteLen(list *List, env *Env) (*Factor, error) {
+	return evalLen("byte_len", list, env, func(s string) int { return len(s) })
+}
+
+func evalLen(name string, list *List, env *Env, length func(string) int) (*Factor, error) {
 	if list == nil || list.Factor == nil {
-		return nil, errors.New("len() with invalid parameter")
+		return nil, errors.New(fmt.Sprintf("%s() with invalid parameter", name))
 	}
 
 	switch list.Factor.Kind {
@@ -472,112 +723,66 @@ func EvalLen(list *List, symlist *SymList) (*Factor, error) {
 		if v, err := cast2string(list.Factor.Value); err != nil {
 			return nil, err
 		} else {
-			return NewFactor(DOUBLE, float64(len(v)), "", "", nil)
+			return NewIntegerFactor(int64(length(v)))
 		}
 	case VARIABLE:
 		if v, err := cast2string(list.Factor.Value); err != nil {
 			return nil, err
 		} else {
 			var value *Factor
-			if value, err = SymbolLookup(symlist, v); err != nil {
+			if value, err = env.Lookup(v); err != nil {
 				return nil, err
 			}
-			if value.Kind != STRING {
-				return nil, err
+			if value.Kind != STRING && !isNumber(value) {
+				return nil, errors.New(fmt.Sprintf("%s() parameter should be 'string'", name))
 			}
-			if v2, err := cast2string(value.Value); err != nil {
+			if v2, err := env.asString(name, value); err != nil {
 				return nil, err
 			} else {
-				return NewFactor(DOUBLE, float64(len(v2)), "", "", nil)
+				return NewIntegerFactor(int64(length(v2)))
 			}
 		}
 	case FUNCTION:
 		if v, ok := list.Factor.Value.(*Func); ok != true {
-			return nil, errors.New("len(): parameter should be 'Func'")
+			return nil, errors.New(fmt.Sprintf("%s(): parameter should be 'Func'", name))
 		} else {
 			var value *Factor
-			value, err := EvalFunc(v, symlist)
+			value, err := EvalFunc(v, env)
 			if err != nil {
 				return nil, err
 			}
-			if value.Kind != STRING {
-				return nil, errors.New("func ret should be 'string'")
-			}
-			if v2, err := cast2string(value.Value); err != nil {
+			if v2, err := env.asString(name, value); err != nil {
 				return nil, errors.New("func ret should be 'string'")
 			} else {
-				return NewFactor(DOUBLE, float64(len(v2)), "", "", nil)
+				return NewIntegerFactor(int64(length(v2)))
 			}
 		}
 	}
 
-	return nil, errors.New(fmt.Sprintf("len with invalid kind '%s'", fkind2str(list.Factor.Kind)))
+	return nil, errors.New(fmt.Sprintf("%s with invalid kind '%s'", name, fkind2str(list.Factor.Kind)))
 }
 
-func EvalMD5(list *List, symlist *SymList) (*Factor, error) {
+func EvalMD5(list *List, env *Env) (*Factor, error) {
 	if list == nil || list.Factor == nil {
 		return nil, errors.New("md5() with invalid parameter")
 	}
 
-	deferr := errors.New("md5() parameter should be 'string'")
-	h := md5.New()
-	for p := list; p != nil; p = p.Next {
-		switch p.Factor.Kind {
-		case STRING:
-			if v, err := cast2string(p.Factor.Value); err != nil {
-				return nil, err
-			} else {
-				io.WriteString(h, v)
-			}
-		case VARIABLE:
-			if v, err := cast2string(p.Factor.Value); err != nil {
-				return nil, err
-			} else {
-				var value *Factor
-				value, err := SymbolLookup(symlist, v)
-				if err != nil {
-					return nil, err
-				}
-				if value.Kind != STRING {
-					return nil, deferr
-				}
-				if v2, err := cast2string(value.Value); err != nil {
-					return nil, err
-				} else {
-					io.WriteString(h, v2)
-				}
-			}
-		case FUNCTION:
-			if v, err := cast2func(p.Factor.Value); err != nil {
-				return nil, err
-			} else {
-				var value *Factor
-				if value, err = EvalFunc(v, symlist); err != nil {
-					return nil, err
-				}
-				if value.Kind != STRING {
-					return nil, deferr
-				}
-				if v2, err := cast2string(value.Value); err != nil {
-					return nil, err
-				} else {
-					io.WriteString(h, v2)
-				}
-			}
-		}
+	v, err := EvalConcat("md5", list, env)
+	if err != nil {
+		return nil, err
 	}
-	return NewFactor(STRING, 0, fmt.Sprintf("%x", h.Sum(nil)), "", nil)
+	return NewFactor(STRING, 0, fmt.Sprintf("%x", md5.Sum([]byte(v))), "", nil)
 }
 
-func EvalCount(symlist *SymList) (*Factor, error) {
+func EvalCount(env *Env) (*Factor, error) {
 	count := 0
-	for p := symlist; p != nil; p = p.Next {
-		count += 1
+	if env.symbols != nil {
+		count = env.symbols.Count()
 	}
-	return NewFactor(DOUBLE, float64(count), "", "", nil)
+	return NewIntegerFactor(int64(count))
 }
 
-func EvalAtoi(list *List, symlist *SymList) (*Factor, error) {
+func EvalAtoi(list *List, env *Env) (*Factor, error) {
 	if list == nil || list.Factor == nil {
 		return nil, errors.New("atoi() with invalid parameter")
 	}
@@ -588,31 +793,23 @@ func EvalAtoi(list *List, symlist *SymList) (*Factor, error) {
 		if v, err := cast2string(list.Factor.Value); err != nil {
 			return nil, err
 		} else {
-			if dbl, err := strconv.ParseFloat(v, 64); err != nil {
-				return nil, err
-			} else {
-				return NewFactor(DOUBLE, dbl, "", "", nil)
-			}
+			return atoiString(v)
 		}
 	case VARIABLE:
 		if v, err := cast2string(list.Factor.Value); err != nil {
 			return nil, err
 		} else {
 			var value *Factor
-			if value, err = SymbolLookup(symlist, v); err != nil {
+			if value, err = env.Lookup(v); err != nil {
 				return nil, err
 			}
-			if value.Kind != STRING {
+			if value.Kind != STRING && !isNumber(value) {
 				return nil, deferr
 			}
-			if v2, err := cast2string(value.Value); err != nil {
+			if v2, err := env.asString("atoi", value); err != nil {
 				return nil, err
 			} else {
-				if dbl, err := strconv.ParseFloat(v2, 64); err != nil {
-					return nil, err
-				} else {
-					return NewFactor(DOUBLE, dbl, "", "", nil)
-				}
+				return atoiString(v2)
 			}
 		}
 	case FUNCTION:
@@ -620,20 +817,16 @@ func EvalAtoi(list *List, symlist *SymList) (*Factor, error) {
 			return nil, err
 		} else {
 			var value *Factor
-			if value, err = EvalFunc(v, symlist); err != nil {
+			if value, err = EvalFunc(v, env); err != nil {
 				return nil, err
 			}
-			if value.Kind != STRING {
+			if value.Kind != STRING && !isNumber(value) {
 				return nil, deferr
 			}
-			if v2, err := cast2string(value.Value); err != nil {
+			if v2, err := env.asString("atoi", value); err != nil {
 				return nil, err
 			} else {
-				if dbl, err := strconv.ParseFloat(v2, 64); err != nil {
-					return nil, err
-				} else {
-					return NewFactor(DOUBLE, dbl, "", "", nil)
-				}
+				return atoiString(v2)
 			}
 		}
 	}
@@ -641,86 +834,119 @@ func EvalAtoi(list *List, symlist *SymList) (*Factor, error) {
 	return nil, errors.New(fmt.Sprintf("atoi with invalid kind '%s'", fkind2str(list.Factor.Kind)))
 }
 
-func EvalItoa(list *List, symlist *SymList) (*Factor, error) {
-	if list == nil || symlist == nil {
+func EvalItoa(list *List, env *Env) (*Factor, error) {
+	if list == nil || list.Factor == nil || env == nil || (list.Next != nil && list.Next.Next != nil) {
 		return nil, errors.New("itoa() with invalid parameter")
 	}
 
-	deferr := errors.New("itoa() parameter should be 'double'")
-	switch list.Factor.Kind {
-	case DOUBLE:
-		if v, err := cast2float64(list.Factor.Value); err != nil {
-			return nil, err
-		} else {
-			return NewFactor(STRING, 0, fmt.Sprintf("%f", v), "", nil)
-		}
-	case VARIABLE:
-		if v, err := cast2string(list.Factor.Value); err != nil {
+	value, err := EvalFactor(list.Factor, env)
+	if err != nil {
+		return nil, err
+	}
+	if value, err = env.asNumber("itoa", value); err != nil {
+		return nil, err
+	}
+	format := ""
+	if list.Next != nil {
+		if format, err = EvalConcat("itoa", list.Next, env); err != nil {
 			return nil, err
-		} else {
-			var value *Factor
-			if value, err = SymbolLookup(symlist, v); err != nil {
-				return nil, err
-			}
-			if value.Kind != DOUBLE {
-				return nil, deferr
-			}
-			if v2, err := cast2float64(value.Value); err != nil {
-				return nil, err
-			} else {
-				return NewFactor(STRING, 0, fmt.Sprintf("%.2f", v2), "", nil)
-			}
-		}
-	case FUNCTION:
-		var value *Factor
-		if v, err := cast2func(list.Factor.Value); err != nil {
-			return nil, err
-		} else {
-			if value, err = EvalFunc(v, symlist); err != nil {
-				return nil, err
-			}
-			if value.Kind != DOUBLE {
-				return nil, deferr
-			}
-			if v2, err := cast2float64(value.Value); err != nil {
-				return nil, err
-			} else {
-				return NewFactor(STRING, 0, fmt.Sprintf("%.2f", v2), "", nil)
-			}
 		}
 	}
-
-	return nil, errors.New(fmt.Sprintf("itoa with invalid kind '%s'", fkind2str(list.Factor.Kind)))
+	if v, err := formatNumber(value, format); err != nil {
+		return nil, err
+	} else {
+		return NewFactor(STRING, 0, v, "", nil)
+	}
 }
 
-func EvalFunc(fn *Func, symlist *SymList) (*Factor, error) {
-	if fn == nil || symlist == nil {
+func EvalFunc(fn *Func, env *Env) (*Factor, error) {
+	if fn == nil || env == nil {
 		return nil, errors.New(fmt.Sprintf("func '%s' with invalid parameter", fnkind2str(fn.Kind)))
 	}
+	if err := env.enter(); err != nil {
+		return nil, err
+	}
+	defer env.leave()
 	switch fn.Kind {
 	case LEN:
-		return EvalLen(fn.List, symlist)
+		return EvalLen(fn.List, env)
+	case BYTE_LEN:
+		return EvalByteLen(fn.List, env)
 	case MD5:
-		return EvalMD5(fn.List, symlist)
+		return EvalMD5(fn.List, env)
 	case COUNT:
-		return EvalCount(symlist)
+		return EvalCount(env)
 	case ATOI:
-		return EvalAtoi(fn.List, symlist)
+		return EvalAtoi(fn.List, env)
 	case ITOA:
-		return EvalItoa(fn.List, symlist)
+		return EvalItoa(fn.List, env)
+	case SHA1, SHA256, SHA512, CRC32:
+		return EvalHash(fn.Kind, fn.List, env)
+	case HMAC_SHA256:
+		return EvalHmac(fn.List, env)
+	case BASE64_ENCODE, BASE64_DECODE, HEX_ENCODE, HEX_DECODE, URL_ENCODE, URL_DECODE:
+		return EvalCodec(fn.Kind, fn.List, env)
+	case SECURE_EQ:
+		return EvalSecureEq(fn.List, env)
+	case NOW:
+		return EvalNow(env)
+	case UNIX:
+		return EvalUnix(fn.List, env)
+	case PARSE_TIME:
+		return EvalParseTime(fn.List, env)
+	case HOUR, WEEKDAY:
+		return EvalClock(fn.Kind, fn.List, env)
+	case DURATION:
+		return EvalDuration(fn.List, env)
+	case SINCE, UNTIL:
+		return EvalSince(fn.Kind, fn.List, env)
+	case TIME_ADD, TIME_SUB:
+		return EvalTimeArith(fn.Kind, fn.List, env)
+	case RATE:
+		return EvalRate(fn.List, env)
+	case COUNT_DISTINCT:
+		return EvalCountDistinct(fn.List, env)
+	case MATCH:
+		return EvalMatch(fn, env)
+	case CONTAINS_ANY:
+		return EvalContainsAny(fn, env)
+	case SIZE:
+		return EvalSize(fn.List, env)
 	}
 
 	return nil, errors.New(fmt.Sprintf("function '%s' not supported", fnkind2str(fn.Kind)))
 }
 
-func EvalCmp(kind TKind_t, lfactor, rfactor *Factor, symlist *SymList) (int, error) {
+/*
+ * resolve a factor to its value: variables are looked up in the symbol
+ * input, functions are evaluated and constants are returned as they are
+ */
+func EvalFactor(factor *Factor, env *Env) (*Factor, error) {
+	switch factor.Kind {
+	case VARIABLE:
+		if v, err := cast2string(factor.Value); err != nil {
+			return nil, err
+		} else {
+			return env.Lookup(v)
+		}
+	case FUNCTION:
+		if v, err := cast2func(factor.Value); err != nil {
+			return nil, err
+		} else {
+			return EvalFunc(v, env)
+		}
+	}
+	return factor, nil
+}
+
+func EvalCmp(kind TKind_t, lfactor, rfactor *Factor, env *Env) (int, error) {
 	lv := lfactor
 	rv := rfactor
 	if lfactor.Kind == VARIABLE {
 		if v, err := cast2string(lfactor.Value); err != nil {
 			return -1, err
 		} else {
-			if lv, err = SymbolLookup(symlist, v); err != nil {
+			if lv, err = env.Lookup(v); err != nil {
 				return -1, err
 			}
 		}
@@ -728,7 +954,7 @@ func EvalCmp(kind TKind_t, lfactor, rfactor *Factor, symlist *SymList) (int, err
 		if v, err := cast2func(lfactor.Value); err != nil {
 			return -1, err
 		} else {
-			if lv, err = EvalFunc(v, symlist); err != nil {
+			if lv, err = EvalFunc(v, env); err != nil {
 				return -1, err
 			}
 		}
@@ -738,7 +964,7 @@ func EvalCmp(kind TKind_t, lfactor, rfactor *Factor, symlist *SymList) (int, err
 		if v, err := cast2string(rfactor.Value); err != nil {
 			return -1, err
 		} else {
-			if rv, err = SymbolLookup(symlist, v); err != nil {
+			if rv, err = env.Lookup(v); err != nil {
 				return -1, err
 			}
 		}
@@ -746,26 +972,36 @@ func EvalCmp(kind TKind_t, lfactor, rfactor *Factor, symlist *SymList) (int, err
 		if v, err := cast2func(rfactor.Value); err != nil {
 			return -1, err
 		} else {
-			if rv, err = EvalFunc(v, symlist); err != nil {
+			if rv, err = EvalFunc(v, env); err != nil {
 				return -1, err
 			}
 		}
 	}
 
+	if v1, v2, err := coerceCmp(env.coercion(), lv, rv); err != nil {
+		return -1, err
+	} else {
+		lv, rv = v1, v2
+	}
+	if lv.Kind != rv.Kind && isNumber(lv) && isNumber(rv) {
+		// an integer mixed with a double compares as double
+		return env.equality().cmp(kind, lv, rv)
+	}
+
 	if lv.Kind != rv.Kind {
 		return 0, nil // just ignore
 	}
 
-	if lv.Kind == DOUBLE {
-		if v1, err := cast2float64(lv.Value); err != nil {
+	if lv.Kind == INTEGER {
+		if v1, err := cast2int64(lv.Value); err != nil {
+			return -1, err
+		} else if v2, err := cast2int64(rv.Value); err != nil {
 			return -1, err
 		} else {
-			if v2, err := cast2float64(rv.Value); err != nil {
-				return -1, err
-			} else {
-				return CmpDbl(kind, v1, v2)
-			}
+			return CmpInt(kind, v1, v2)
 		}
+	} else if lv.Kind == DOUBLE {
+		return env.equality().cmp(kind, lv, rv)
 	} else if lv.Kind == STRING {
 		if v1, err := cast2string(lv.Value); err != nil {
 			return -1, err
@@ -773,7 +1009,7 @@ func EvalCmp(kind TKind_t, lfactor, rfactor *Factor, symlist *SymList) (int, err
 			if v2, err := cast2string(rv.Value); err != nil {
 				return -1, err
 			} else {
-				return CmpStr(kind, v1, v2)
+				return CmpStr(kind, env.comparable(v1), env.comparable(v2))
 			}
 		}
 	}
@@ -781,23 +1017,12 @@ func EvalCmp(kind TKind_t, lfactor, rfactor *Factor, symlist *SymList) (int, err
 	return -1, errors.New(fmt.Sprintf("operator '%s' not supported", tkind2str(kind)))
 }
 
+/*
+ * compare two doubles as equal within 0.001, as parsers do unless
+ * WithNumericEquality() says otherwise
+ */
 func CmpDbl(kind TKind_t, d1, d2 float64) (int, error) {
-	switch kind {
-	case GT:
-		return bool2int(d1 > d2), nil
-	case LT:
-		return bool2int(d1 < d2), nil
-	case EQ:
-		return bool2int(math.Abs(d1-d2) < 0.001), nil
-	case NE:
-		return bool2int(math.Abs(d1-d2) > 0.001), nil
-	case GE:
-		return bool2int(d1 > d2 || math.Abs(d1-d2) < 0.001), nil
-	case LE:
-		return bool2int(d1 < d2 || math.Abs(d1-d2) < 0.001), nil
-	}
-
-	return -1, errors.New(fmt.Sprintf("double operator '%s' not supported", tkind2str(kind)))
+	return defaultEquality.cmp(kind, &Factor{DOUBLE, d1}, &Factor{DOUBLE, d2})
 }
 
 func CmpStr(kind TKind_t, s1, s2 string) (int, error) {
@@ -0,0 +1,74 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+ * a name declared at the top of a rule script:
+ *	let sig = md5(key, '@163.com')	binds the value of a factor
+ *	def is_bot = ua # 'bot|spider'	binds the result (0 or 1) of an expr
+ * later statements use the name as a variable, or alone as a term;
+ * the value is evaluated when first used, at most once per Parse call
+ */
+type Binding struct {
+	Name  string
+	Value interface{} // *Factor for let, *Expr for def
+
+	scope map[string]*Binding // declarations seen by Value
+	file  string              // declared in, '' for the rule itself
+}
+
+func NewBinding(name string, value interface{}) (*Binding, error) {
+	switch value.(type) {
+	case *Factor, *Expr:
+	default:
+		return nil, errors.New(fmt.Sprintf("'%s' bound to invalid value", name))
+	}
+	b := new(Binding)
+	b.Name = name
+	b.Value = value
+	return b, nil
+}
+
+/*
+ * value of a binding within a Parse call
+ */
+type bound struct {
+	value *Factor
+	err   error
+	busy  bool
+}
+
+func EvalBinding(b *Binding, env *Env) (*Factor, error) {
+	if v, ok := env.bound[b]; ok {
+		if v.busy {
+			return nil, errors.New(fmt.Sprintf("'%s' refers to itself", b.Name))
+		}
+		return v.value, v.err
+	}
+
+	v := &bound{busy: true}
+	if env.bound == nil {
+		env.bound = make(map[*Binding]*bound)
+	}
+	env.bound[b] = v
+
+	// declarations are outside of any(), all() and none(), and see the
+	// names of the file they are declared in
+	outer, bindings := env.it, env.bindings
+	env.it, env.bindings = nil, b.scope
+	switch u := b.Value.(type) {
+	case *Factor:
+		v.value, v.err = EvalFactor(u, env)
+	case *Expr:
+		var rc int
+		if rc, v.err = EvalExpr(u, env); v.err == nil {
+			v.value, v.err = NewFactor(DOUBLE, float64(rc), "", "", nil)
+		}
+	}
+	env.it, env.bindings = outer, bindings
+	v.busy = false
+	return v.value, v.err
+}
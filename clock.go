@@ -0,0 +1,209 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+/*
+ * time values are float64 seconds since the unix epoch, durations are
+ * float64 seconds, so both compare with plain numbers:
+ * since(unix(ts)) > duration('5m') => 1
+ */
+
+var timeLayouts = map[string]string{
+	"RFC3339":  time.RFC3339,
+	"RFC1123":  time.RFC1123,
+	"RFC822":   time.RFC822,
+	"DateTime": "2006-01-02 15:04:05",
+	"DateOnly": "2006-01-02",
+}
+
+func time2float64(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+func float642time(v float64, loc *time.Location) time.Time {
+	sec, frac := math.Modf(v)
+	return time.Unix(int64(sec), int64(frac*1e9)).In(loc)
+}
+
+/*
+ * evaluate the only parameter of a time builtin as seconds, a string is
+ * a number only as WithCoercion() allows
+ */
+func evalSeconds(name string, list *List, env *Env) (float64, error) {
+	if list == nil || list.Factor == nil || list.Next != nil {
+		return 0, errors.New(fmt.Sprintf("%s() with invalid parameter", name))
+	}
+
+	value, err := EvalFactor(list.Factor, env)
+	if err != nil {
+		return 0, err
+	}
+	if value, err = env.asNumber(name, value); err != nil {
+		return 0, err
+	}
+	return cast2number(value)
+}
+
+func EvalNow(env *Env) (*Factor, error) {
+	return NewFactor(DOUBLE, time2float64(env.Now()), "", "", nil)
+}
+
+/*
+ * unix(): current time in whole seconds
+ * unix(ts): timestamp in seconds given as number or, whatever the
+ * coercion, as numeric string: unix() is how the other time builtins
+ * take a timestamp from a query string
+ */
+func EvalUnix(list *List, env *Env) (*Factor, error) {
+	if list == nil {
+		return NewFactor(DOUBLE, float64(env.Now().Unix()), "", "", nil)
+	}
+	if list.Factor == nil || list.Next != nil {
+		return nil, errors.New("unix() with invalid parameter")
+	}
+
+	value, err := EvalFactor(list.Factor, env)
+	if err != nil {
+		return nil, err
+	}
+	if value.Kind == STRING {
+		v, err := strconv.ParseFloat(value.Value.(string), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("unix() parameter should be 'double'")
+		}
+		return NewFactor(DOUBLE, v, "", "", nil)
+	}
+	if value, err = env.asNumber("unix", value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+/*
+ * parse_time(s, layout): layout is a go time layout or one of the names
+ * in timeLayouts, times without zone are taken in the clock's location
+ */
+func EvalParseTime(list *List, env *Env) (*Factor, error) {
+	if list == nil || list.Next == nil || list.Next.Next != nil {
+		return nil, errors.New("parse_time() with invalid parameter")
+	}
+
+	s, err := EvalConcat("parse_time", &List{Factor: list.Factor}, env)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := EvalConcat("parse_time", list.Next, env)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := timeLayouts[layout]; ok {
+		layout = v
+	}
+
+	t, err := time.ParseInLocation(layout, s, env.Now().Location())
+	if err != nil {
+		return nil, err
+	}
+	return NewFactor(DOUBLE, time2float64(t), "", "", nil)
+}
+
+/*
+ * hour(t), weekday(t): fields of t in the clock's location, t defaults
+ * to now(); weekday counts from 0 (Sunday)
+ */
+func EvalClock(kind FnKind_t, list *List, env *Env) (*Factor, error) {
+	now := env.Now()
+	t := now
+	if list != nil {
+		if v, err := evalSeconds(fnkind2str(kind), list, env); err != nil {
+			return nil, err
+		} else {
+			t = float642time(v, now.Location())
+		}
+	}
+
+	switch kind {
+	case HOUR:
+		return NewFactor(DOUBLE, float64(t.Hour()), "", "", nil)
+	case WEEKDAY:
+		return NewFactor(DOUBLE, float64(t.Weekday()), "", "", nil)
+	}
+
+	return nil, errors.New(fmt.Sprintf("clock '%s' not supported", fnkind2str(kind)))
+}
+
+/*
+ * duration('1h30m'): seconds of a go duration string
+ */
+func EvalDuration(list *List, env *Env) (*Factor, error) {
+	if list == nil || list.Factor == nil || list.Next != nil {
+		return nil, errors.New("duration() with invalid parameter")
+	}
+
+	s, err := EvalConcat("duration", list, env)
+	if err != nil {
+		return nil, err
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewFactor(DOUBLE, d.Seconds(), "", "", nil)
+}
+
+/*
+ * since(t): seconds elapsed from t to now
+ * until(t): seconds left from now to t
+ */
+func EvalSince(kind FnKind_t, list *List, env *Env) (*Factor, error) {
+	v, err := evalSeconds(fnkind2str(kind), list, env)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time2float64(env.Now())
+	switch kind {
+	case SINCE:
+		return NewFactor(DOUBLE, now-v, "", "", nil)
+	case UNTIL:
+		return NewFactor(DOUBLE, v-now, "", "", nil)
+	}
+
+	return nil, errors.New(fmt.Sprintf("duration '%s' not supported", fnkind2str(kind)))
+}
+
+/*
+ * time_add(t, d): t moved by d seconds, d may be negative
+ * time_sub(t, u): seconds from u to t, negative when t is earlier
+ * durations being seconds as well, both add and subtract durations too:
+ * time_add(unix(ts), duration('5m')) < now()
+ */
+func EvalTimeArith(kind FnKind_t, list *List, env *Env) (*Factor, error) {
+	name := fnkind2str(kind)
+	if list == nil || list.Next == nil || list.Next.Next != nil {
+		return nil, errors.New(fmt.Sprintf("%s() with invalid parameter", name))
+	}
+	x, err := evalSeconds(name, &List{Factor: list.Factor}, env)
+	if err != nil {
+		return nil, err
+	}
+	y, err := evalSeconds(name, list.Next, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case TIME_ADD:
+		return NewFactor(DOUBLE, x+y, "", "", nil)
+	case TIME_SUB:
+		return NewFactor(DOUBLE, x-y, "", "", nil)
+	}
+
+	return nil, errors.New(fmt.Sprintf("time function '%s' not supported", name))
+}
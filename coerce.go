@@ -0,0 +1,137 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+)
+
+type CKind_t int // for coercion of strings and numbers
+
+const (
+	IGNORE  = CKind_t(0) // a string never equals a number, builtins take their own kind only (default)
+	STRICT  = CKind_t(1) // a string meeting a number is an error
+	NUMERIC = CKind_t(2) // a string that reads as a number is that number
+	TEXT    = CKind_t(3) // a number meeting a string is its text
+)
+
+func ckind2str(kind CKind_t) string {
+	switch kind {
+	case IGNORE:
+		return "ignore"
+	case STRICT:
+		return "strict"
+	case NUMERIC:
+		return "numeric"
+	case TEXT:
+		return "text"
+	}
+	return fmt.Sprintf("%d", int(kind))
+}
+
+func (env *Env) coercion() CKind_t {
+	if env.parser != nil {
+		return env.parser.coercion
+	}
+	return IGNORE
+}
+
+/*
+ * make the two sides of a comparison the same kind when one is a string
+ * and the other a number: with NUMERIC the string is read as a number,
+ * and the number written as text if the string is no number (so '1e3'
+ * equals 1000 and 'abc' differs from 10); with TEXT the number is written
+ * as text. other pairs are left as they are
+ */
+func coerceCmp(coercion CKind_t, lv, rv *Factor) (*Factor, *Factor, error) {
+	if !(lv.Kind == STRING && isNumber(rv)) && !(isNumber(lv) && rv.Kind == STRING) {
+		return lv, rv, nil
+	}
+
+	switch coercion {
+	case STRICT:
+		return nil, nil, errors.New(fmt.Sprintf("can't compare '%s' with '%s'", fkind2str(lv.Kind), fkind2str(rv.Kind)))
+	case NUMERIC:
+		if lv.Kind == STRING {
+			if v, err := atoiFactor(lv.Value.(string)); err == nil {
+				return v, rv, nil
+			}
+		} else if v, err := atoiFactor(rv.Value.(string)); err == nil {
+			return lv, v, nil
+		}
+		fallthrough
+	case TEXT:
+		if lv.Kind == STRING {
+			v, err := textFactor(rv)
+			return lv, v, err
+		}
+		v, err := textFactor(lv)
+		return v, rv, err
+	}
+	return lv, rv, nil
+}
+
+func textFactor(f *Factor) (*Factor, error) {
+	s, err := formatNumber(f, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewFactor(STRING, 0, s, "", nil)
+}
+
+/*
+ * the text of a builtin parameter that should be a string, numbers are
+ * written as text unless the policy is IGNORE or STRICT
+ */
+func (env *Env) asString(name string, value *Factor) (string, error) {
+	switch {
+	case value.Kind == STRING:
+		return cast2string(value.Value)
+	case isNumber(value) && (env.coercion() == NUMERIC || env.coercion() == TEXT):
+		return formatNumber(value, "")
+	}
+	return "", errors.New(fmt.Sprintf("%s() parameter should be 'string'", name))
+}
+
+/*
+ * the value of a builtin parameter that should be a number, strings that
+ * read as one are taken unless the policy is IGNORE or STRICT
+ */
+func (env *Env) asNumber(name string, value *Factor) (*Factor, error) {
+	switch {
+	case isNumber(value):
+		return value, nil
+	case value.Kind == STRING && (env.coercion() == NUMERIC || env.coercion() == TEXT):
+		if v, err := atoiFactor(value.Value.(string)); err == nil {
+			return v, nil
+		}
+		return nil, errors.New(fmt.Sprintf("%s() parameter '%s' is not a number", name, value.Value))
+	}
+	return nil, errors.New(fmt.Sprintf("%s() parameter should be 'double'", name))
+}
+
+/*
+ * with STRICT, a comparison of a string constant with a number constant
+ * is an error of the rule
+ */
+func (l *ruleLexer) checkCoercion(term *Term) {
+	if l.parser.coercion != STRICT || !isConstant(term.Left) {
+		return
+	}
+	check := func(f *Factor) {
+		if isConstant(f) && ((term.Left.Kind == STRING) != (f.Kind == STRING)) {
+			l.fail(fmt.Sprintf("can't compare '%s' with '%s'", fkind2str(term.Left.Kind), fkind2str(f.Kind)))
+		}
+	}
+	switch v := term.Right.(type) {
+	case *Factor:
+		check(v)
+	case *List:
+		for p := v; p != nil; p = p.Next {
+			check(p.Factor)
+		}
+	}
+}
+
+func isConstant(f *Factor) bool {
+	return f != nil && (f.Kind == STRING || isNumber(f))
+}
@@ -0,0 +1,521 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+/*
+ * error of NewParser, Line and Column (both from 1) locate the token
+ * where the rule was rejected, they are 0 when the error is about the
+ * rule as a whole; File names the imported file the token is in, it is
+ * empty for the rule given to NewParser
+ */
+type CompileError struct {
+	File   string
+	Line   int
+	Column int
+	Err    string
+}
+
+func (e *CompileError) Error() string {
+	where := ""
+	if e.File != "" {
+		where = e.File + ": "
+	}
+	if e.Line == 0 {
+		return where + e.Err
+	}
+	return fmt.Sprintf("%sline %d column %d: %s", where, e.Line, e.Column, e.Err)
+}
+
+/*
+ * compile time limits of a parser, 0 means unlimited
+ */
+type limits struct {
+	maxSourceSize int
+	maxNodes      int
+	maxNesting    int
+	maxListLen    int
+	maxRegexSize  int
+}
+
+/*
+ * lexer handed to yyParse: it keeps the position of the last token and
+ * the result of the parse, and enforces the compile time limits
+ */
+type ruleLexer struct {
+	*Lexer
+	parser   *Parser
+	grammer  *Grammer
+	bindings map[string]*Binding
+	line     int
+	column   int
+	nesting  int
+	params   map[string]bool
+
+	// the token read ahead and the last two tokens returned, to tell
+	// keywords from variables of the same name
+	ahead *lexToken
+	last  [2]int
+
+	// imports: the file being parsed, the lexer of the importing file
+	// and the declarations of every file imported so far
+	file     string
+	parent   *ruleLexer
+	imported map[string]map[string]*Binding
+}
+
+func newRuleLexer(h *Parser, lex *Lexer) *ruleLexer {
+	l := new(ruleLexer)
+	l.Lexer = lex
+	l.parser = h
+	l.bindings = make(map[string]*Binding)
+	l.imported = make(map[string]map[string]*Binding)
+	l.params = make(map[string]bool)
+	return l
+}
+
+/*
+ * a token with its text and position, line is 0 at the end of input
+ */
+type lexToken struct {
+	tok          int
+	lval         yySymType
+	text         string
+	line, column int
+}
+
+func (l *ruleLexer) read() *lexToken {
+	if t := l.ahead; t != nil {
+		l.ahead = nil
+		return t
+	}
+	t := new(lexToken)
+	if t.tok = l.Lexer.Lex(&t.lval); t.tok != 0 {
+		t.text = l.Lexer.Text()
+		t.line, t.column = l.Lexer.l+1, l.Lexer.c+1
+		l.Lexer.advance()
+	}
+	return t
+}
+
+func (l *ruleLexer) peek() int {
+	if l.ahead == nil {
+		l.ahead = l.read()
+	}
+	return l.ahead.tok
+}
+
+/*
+ * names of builtins, quantifiers and declarations are keywords only where
+ * they can start one: a builtin or quantifier before '(', let and def
+ * before the name they declare, import and include before the file name
+ * and as after import 'file'. elsewhere they are variables, so 'size ==
+ * 10' looks up the input symbol size
+ */
+func (l *ruleLexer) keyword(t *lexToken) int {
+	switch t.tok {
+	case FUNC, QUANT:
+		if l.peek() == LPAREN {
+			return t.tok
+		}
+	case LET, DEF:
+		switch l.peek() {
+		case VAR, FUNC, QUANT, LET, DEF, IMPORT, INCLUDE, AS:
+			return t.tok
+		}
+	case IMPORT, INCLUDE:
+		if l.peek() == STR {
+			return t.tok
+		}
+	case AS:
+		if l.last == [2]int{IMPORT, STR} {
+			return t.tok
+		}
+	default:
+		return t.tok
+	}
+	t.lval.str = t.text
+	return VAR
+}
+
+// a variable name, as the VAR rule of rule.nex reads it
+var nameRegex = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*(\.[_a-zA-Z0-9]+)*(@[_a-zA-Z][_a-zA-Z0-9]*)?$`)
+
+/*
+ * '#i' and '!#i' run into a name are '#' and '!#' before a name starting
+ * with i: 'x #ip' matches x with the variable ip, 'x #i p' with p
+ */
+func (l *ruleLexer) caseless(t *lexToken) {
+	if t.tok != CMP || (t.lval.fn != int(MAI) && t.lval.fn != int(NMI)) {
+		return
+	}
+	l.peek()
+	next := l.ahead
+	if next.line != t.line || next.column != t.column+len(t.text) || !nameRegex.MatchString("i"+next.text) {
+		return
+	}
+	if t.lval.fn == int(MAI) {
+		t.lval.fn = int(MA)
+	} else {
+		t.lval.fn = int(NM)
+	}
+	t.text = strings.TrimSuffix(t.text, "i")
+	next.tok, next.text, next.column = VAR, "i"+next.text, next.column-1
+	next.lval.str = next.text
+}
+
+func (l *ruleLexer) Lex(lval *yySymType) int {
+	t := l.read()
+	l.caseless(t)
+	tok := l.keyword(t)
+	lval.str, lval.dval, lval.ival, lval.fn = t.lval.str, t.lval.dval, t.lval.ival, t.lval.fn
+	if t.line > 0 {
+		// at the end of input the lexer has nothing left to locate
+		l.line, l.column = t.line, t.column
+	}
+	l.last = [2]int{l.last[1], tok}
+	switch tok {
+	case BADNUM:
+		l.fail(fmt.Sprintf("malformed number '%s'", lval.str))
+	case LPAREN:
+		l.nesting += 1
+		if max := l.parser.limits.maxNesting; max > 0 && l.nesting > max {
+			l.fail(fmt.Sprintf("nesting deeper than %d", max))
+		}
+	case RPAREN:
+		l.nesting -= 1
+	}
+	return tok
+}
+
+/*
+ * move the position kept in the l (line) and c (column, in runes) fields
+ * of the lexer, both from 0, past the text of the last match. the rules
+ * of rule.nex skipping spaces and comments call it, ruleLexer.Lex() does
+ * for the tokens
+ */
+func (yylex *Lexer) advance() {
+	for _, r := range yylex.Text() {
+		if r == '\n' {
+			yylex.l, yylex.c = yylex.l+1, 0
+		} else {
+			yylex.c += 1
+		}
+	}
+}
+
+func (l *ruleLexer) Error(e string) {
+	l.fail(e)
+}
+
+/*
+ * abort the parse with an error at the current token, NewParser
+ * recovers it
+ */
+func (l *ruleLexer) fail(e string) {
+	panic(&CompileError{l.file, l.line, l.column, e})
+}
+
+func (l *ruleLexer) errorf(e interface{}) *CompileError {
+	if v, ok := e.(*CompileError); ok {
+		return v
+	}
+	return &CompileError{l.file, l.line, l.column, fmt.Sprint(e)}
+}
+
+/*
+ * declare a let or def binding, names are declared once
+ */
+func (l *ruleLexer) bind(name string, value interface{}) {
+	b, err := NewBinding(name, value)
+	if err != nil {
+		l.fail(err.Error())
+	}
+	// names in the value are looked up among the declarations of this file
+	b.scope = l.bindings
+	b.file = l.file
+	if chain := l.cycle(name, value, map[*Binding]bool{}); chain != nil {
+		l.fail(fmt.Sprintf("'%s' refers to itself: %s -> %s", name, name, strings.Join(chain, " -> ")))
+	}
+	l.declare(name, b)
+}
+
+/*
+ * the declarations leading from value back to name, nil if there are none;
+ * declarations of imported files can't see name
+ */
+func (l *ruleLexer) cycle(name string, value interface{}, seen map[*Binding]bool) []string {
+	refs := []string{}
+	switch v := value.(type) {
+	case *Factor:
+		refs = refsFactor(v, refs)
+	case *Expr:
+		refs = refsExpr(v, refs)
+	}
+	for _, ref := range refs {
+		if ref == name {
+			return []string{ref}
+		}
+		b, ok := l.bindings[ref]
+		if !ok || seen[b] || b.file != l.file {
+			continue
+		}
+		seen[b] = true
+		if chain := l.cycle(name, b.Value, seen); chain != nil {
+			return append([]string{ref}, chain...)
+		}
+	}
+	return nil
+}
+
+func (l *ruleLexer) declare(name string, b *Binding) {
+	if old, ok := l.bindings[name]; ok {
+		// a file reached by two includes declares the same bindings twice
+		if old == b && b.file != "" {
+			return
+		}
+		l.fail(fmt.Sprintf("'%s' already declared", name))
+	}
+	l.bindings[name] = b
+}
+
+/*
+ * a name used alone as a term must be declared by let or def
+ */
+func (l *ruleLexer) checkRef(name string) {
+	if _, ok := l.bindings[name]; !ok {
+		l.fail(fmt.Sprintf("'%s' is not declared by let or def", name))
+	}
+}
+
+/*
+ * parameters must be bound by WithParams() when the rule is compiled
+ */
+func (l *ruleLexer) checkParam(name string) {
+	if _, ok := l.parser.params[name]; !ok {
+		l.fail(fmt.Sprintf("parameter '$%s' not bound", name))
+	}
+	l.params[name] = true
+}
+
+func (l *ruleLexer) checkList(list *List) {
+	max := l.parser.limits.maxListLen
+	if max <= 0 {
+		return
+	}
+	n := 0
+	for p := list; p != nil; p = p.Next {
+		n += 1
+	}
+	if n > max {
+		l.fail(fmt.Sprintf("list longer than %d", max))
+	}
+}
+
+/*
+ * the pattern of match() is compiled here, as constant patterns of regex
+ * terms are by checkTerm()
+ */
+func (l *ruleLexer) checkFunc(fn *Func) {
+	l.checkList(fn.List)
+	if fn.Kind == MATCH {
+		if err := CompileMatch(fn, l.parser.regexSyntax, l.parser.normalization); err != nil {
+			l.fail(err.Error())
+		}
+	}
+	if fn.Regex != nil {
+		l.checkRegex(fn.Regex)
+	}
+}
+
+/*
+ * constant patterns of regex terms are compiled here, with the syntax
+ * chosen by WithRegexSyntax(); other patterns are left to evaluation.
+ * globs are compiled by NewTerm() already, only their size is checked.
+ * comparisons of constants are checked against WithCoercion()
+ */
+func (l *ruleLexer) checkTerm(term *Term) {
+	switch term.Kind {
+	case MA, NM, MAI, NMI:
+	case GL, NG:
+		l.checkRegex(term.Right.(*regexp.Regexp))
+		return
+	case IN, NI, GT, LT, EQ, NE, GE, LE:
+		l.checkCoercion(term)
+		return
+	default:
+		return
+	}
+	v, ok := term.Right.(*Factor)
+	if !ok || v.Kind != STRING {
+		return
+	}
+	pattern := normalize(l.parser.normalization, v.Value.(string))
+	regex, err := CompileRegex(pattern, l.parser.regexSyntax, foldkind(term.Kind))
+	if err != nil {
+		l.fail(err.Error())
+	}
+	l.checkRegex(regex)
+	term.Right = regex
+}
+
+/*
+ * patterns are measured as RE2, which accepts POSIX patterns and the
+ * folded ones alike
+ */
+func (l *ruleLexer) checkRegex(regex *regexp.Regexp) {
+	max := l.parser.limits.maxRegexSize
+	if max <= 0 {
+		return
+	}
+	if n, err := regexSize(regex.String(), syntax.Perl); err != nil {
+		l.fail(err.Error())
+	} else if n > max {
+		l.fail(fmt.Sprintf("regex '%s' larger than %d", regex.String(), max))
+	}
+}
+
+/*
+ * size of a regex as the number of instructions of its program
+ */
+func regexSize(pattern string, flags syntax.Flags) (int, error) {
+	re, err := syntax.Parse(pattern, flags)
+	if err != nil {
+		return 0, err
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return 0, err
+	}
+	return len(prog.Inst), nil
+}
+
+/*
+ * check the limits that concern the whole rule
+ */
+func (l *ruleLexer) checkRule() error {
+	if max := l.parser.limits.maxNodes; max > 0 {
+		n := countGrammer(l.grammer)
+		for _, b := range l.bindings {
+			switch v := b.Value.(type) {
+			case *Factor:
+				n += 1 + countFactor(v)
+			case *Expr:
+				n += 1 + countExpr(v)
+			}
+		}
+		if n > max {
+			return &CompileError{"", 0, 0, fmt.Sprintf("rule has %d nodes, more than %d", n, max)}
+		}
+	}
+	return nil
+}
+
+func countGrammer(g *Grammer) int {
+	n := 0
+	for ; g != nil; g = g.Grammer {
+		n += 1 + countExpr(g.Expr)
+	}
+	return n
+}
+
+func countExpr(e *Expr) int {
+	n := 0
+	for ; e != nil; e = e.Left {
+		n += 1 + countTerm(e.Right)
+	}
+	return n
+}
+
+func countTerm(t *Term) int {
+	if t == nil {
+		return 0
+	}
+	n := 1 + countFactor(t.Left)
+	switch v := t.Right.(type) {
+	case *Factor:
+		n += countFactor(v)
+	case *List:
+		n += countList(v)
+	case *Expr:
+		n += countExpr(v)
+	case *regexp.Regexp:
+		n += 1
+	}
+	return n
+}
+
+func countFactor(f *Factor) int {
+	if f == nil {
+		return 0
+	}
+	switch v := f.Value.(type) {
+	case *Func:
+		return 2 + countList(v.List)
+	case *List:
+		return 1 + countList(v)
+	}
+	return 1
+}
+
+func countList(list *List) int {
+	n := 0
+	for p := list; p != nil; p = p.Next {
+		n += 1 + countFactor(p.Factor)
+	}
+	return n
+}
+
+/*
+ * names of the variables in an expr, appended to refs
+ */
+func refsExpr(e *Expr, refs []string) []string {
+	for ; e != nil; e = e.Left {
+		refs = refsTerm(e.Right, refs)
+	}
+	return refs
+}
+
+func refsTerm(t *Term, refs []string) []string {
+	if t == nil {
+		return refs
+	}
+	refs = refsFactor(t.Left, refs)
+	switch v := t.Right.(type) {
+	case *Factor:
+		refs = refsFactor(v, refs)
+	case *List:
+		refs = refsList(v, refs)
+	case *Expr:
+		refs = refsExpr(v, refs)
+	}
+	return refs
+}
+
+func refsFactor(f *Factor, refs []string) []string {
+	if f == nil {
+		return refs
+	}
+	switch v := f.Value.(type) {
+	case string:
+		if f.Kind == VARIABLE {
+			refs = append(refs, v)
+		}
+	case *Func:
+		refs = refsList(v.List, refs)
+	case *List:
+		refs = refsList(v, refs)
+	}
+	return refs
+}
+
+func refsList(list *List, refs []string) []string {
+	for p := list; p != nil; p = p.Next {
+		refs = refsFactor(p.Factor, refs)
+	}
+	return refs
+}
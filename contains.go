@@ -0,0 +1,164 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+ * Aho-Corasick automaton over the bytes of a keyword list, finds any of
+ * the keywords in a single pass whatever the number of keywords
+ */
+type Matcher struct {
+	keywords []string
+	fold     bool
+	nodes    []acNode
+}
+
+type acNode struct {
+	next map[byte]int
+	fail int
+	out  int // keyword ending at this node, -1 if none
+	dict int // nearest node on the fail chain with out >= 0, -1 if none
+}
+
+/*
+ * fold matches ignoring case, keywords must not be empty
+ */
+func NewMatcher(keywords []string, fold bool) (*Matcher, error) {
+	m := new(Matcher)
+	m.keywords = keywords
+	m.fold = fold
+	m.nodes = []acNode{{next: map[byte]int{}, out: -1, dict: -1}}
+
+	for i, kw := range keywords {
+		if kw == "" {
+			return nil, errors.New("empty keyword")
+		}
+		if fold {
+			kw = strings.ToLower(kw)
+		}
+		n := 0
+		for j := 0; j < len(kw); j++ {
+			next, ok := m.nodes[n].next[kw[j]]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: map[byte]int{}, out: -1, dict: -1})
+				m.nodes[n].next[kw[j]] = next
+			}
+			n = next
+		}
+		if m.nodes[n].out < 0 {
+			m.nodes[n].out = i
+		}
+	}
+
+	// fail links in breadth first order, so shallower nodes are done first
+	queue := []int{}
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for c, child := range m.nodes[n].next {
+			f := m.nodes[n].fail
+			for f != 0 && !m.hasNext(f, c) {
+				f = m.nodes[f].fail
+			}
+			if next, ok := m.nodes[f].next[c]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			fail := m.nodes[child].fail
+			if m.nodes[fail].out >= 0 {
+				m.nodes[child].dict = fail
+			} else {
+				m.nodes[child].dict = m.nodes[fail].dict
+			}
+			queue = append(queue, child)
+		}
+	}
+	return m, nil
+}
+
+func (m *Matcher) hasNext(n int, c byte) bool {
+	_, ok := m.nodes[n].next[c]
+	return ok
+}
+
+/*
+ * the keyword that ends first in s, the longest one if several end at
+ * the same byte
+ */
+func (m *Matcher) Find(s string) (string, bool) {
+	if m.fold {
+		s = strings.ToLower(s)
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		for n != 0 && !m.hasNext(n, c) {
+			n = m.nodes[n].fail
+		}
+		if next, ok := m.nodes[n].next[c]; ok {
+			n = next
+		}
+		if out := m.nodes[n].out; out >= 0 {
+			return m.keywords[out], true
+		}
+		if dict := m.nodes[n].dict; dict >= 0 {
+			return m.keywords[m.nodes[dict].out], true
+		}
+	}
+	return "", false
+}
+
+/*
+ * contains_any(x, ('kw1', 'kw2', ...)): the keyword found in x, or '' if
+ * there is none, so the call alone tests for any keyword;
+ * contains_any(x, (...), 'i') ignores case. keywords must be string
+ * constants, the automaton is built once here
+ */
+func CompileContainsAny(fn *Func) error {
+	list := fn.List
+	if list == nil || list.Next == nil || list.Next.Factor.Kind != LIST ||
+		(list.Next.Next != nil && list.Next.Next.Next != nil) {
+		return errors.New("contains_any() with invalid parameter")
+	}
+
+	fold := false
+	if flag := list.Next.Next; flag != nil {
+		if flag.Factor.Kind != STRING || flag.Factor.Value.(string) != "i" {
+			return errors.New("contains_any() flag should be 'i'")
+		}
+		fold = true
+	}
+
+	keywords := []string{}
+	for p := list.Next.Factor.Value.(*List); p != nil; p = p.Next {
+		if p.Factor.Kind != STRING {
+			return errors.New("contains_any() keyword should be a 'string' constant")
+		}
+		keywords = append(keywords, p.Factor.Value.(string))
+	}
+	m, err := NewMatcher(keywords, fold)
+	if err != nil {
+		return errors.New(fmt.Sprintf("contains_any() %s", err))
+	}
+	fn.Matcher = m
+	return nil
+}
+
+func EvalContainsAny(fn *Func, env *Env) (*Factor, error) {
+	if fn.Matcher == nil {
+		return nil, errors.New("contains_any() with invalid parameter")
+	}
+
+	v, err := EvalConcat("contains_any", &List{Factor: fn.List.Factor}, env)
+	if err != nil {
+		return nil, err
+	}
+	kw, _ := fn.Matcher.Find(v)
+	return NewFactor(STRING, 0, kw, "", nil)
+}
@@ -0,0 +1,177 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+/*
+ * evaluation environment of a single Parse call: the symbol input, the
+ * options of the parser being evaluated and the budgets spent so far
+ */
+type Env struct {
+	symbols  SymbolProvider
+	resolved map[string]*resolved
+	parser   *Parser
+	ctx      context.Context
+	steps    int
+	depth    int
+	it       *scope
+	bound    map[*Binding]*bound
+
+	// declarations visible to the expression being evaluated, the ones of
+	// the rule unless within an imported declaration
+	bindings map[string]*Binding
+}
+
+/*
+ * item bound to 'it' inside any(), all() and none()
+ */
+type scope struct {
+	item *SymList
+}
+
+/*
+ * returned when an evaluation runs over one of the budgets set by
+ * WithMaxSteps(), WithMaxDepth() or WithMaxValueSize()
+ */
+type BudgetExceeded struct {
+	Budget string // "steps", "depth" or "value size"
+	Limit  int
+}
+
+func (e *BudgetExceeded) Error() string {
+	return fmt.Sprintf("evaluation budget exceeded: %s over %d", e.Budget, e.Limit)
+}
+
+func NewEnv(h *Parser, symbols SymbolProvider) *Env {
+	env := new(Env)
+	env.symbols = symbols
+	env.parser = h
+	if h != nil {
+		env.bindings = h.bindings
+	}
+	env.ctx = context.Background()
+	return env
+}
+
+func (env *Env) Lookup(name string) (*Factor, error) {
+	if strings.HasPrefix(name, "$") {
+		if env.parser != nil {
+			if v, ok := env.parser.params[name[1:]]; ok {
+				return v, nil
+			}
+		}
+		return nil, errors.New(fmt.Sprintf("parameter '%s' not bound", name))
+	}
+
+	var value *Factor
+	var err error
+	if env.inItem(name) {
+		// a scalar item is the symbol '', fields of an object item keep their names
+		if value, err = SymbolLookup(env.it.item, strings.TrimPrefix(name[2:], ".")); err != nil {
+			return nil, errors.New(fmt.Sprintf("symbol '%s' not found", name))
+		}
+	} else if b, ok := env.bindings[name]; ok {
+		return EvalBinding(b, env)
+	} else if value, err = env.lookupSymbol(name); err != nil {
+		return nil, err
+	}
+	if value.Kind == STRING {
+		if err := env.checkSize(len(value.Value.(string))); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+/*
+ * name is 'it' or a field of it, inside any(), all() or none()
+ */
+func (env *Env) inItem(name string) bool {
+	return env.it != nil && (name == "it" || strings.HasPrefix(name, "it.") || strings.HasPrefix(name, "it@"))
+}
+
+func (env *Env) Now() time.Time {
+	if env.parser != nil && env.parser.clock != nil {
+		return env.parser.clock()
+	}
+	return time.Now()
+}
+
+func (env *Env) Store() StateStore {
+	if env.parser != nil {
+		return env.parser.store
+	}
+	return nil
+}
+
+/*
+ * how doubles compare, as set by WithNumericEquality()
+ */
+func (env *Env) equality() *equality {
+	if env.parser != nil && env.parser.equality != nil {
+		return env.parser.equality
+	}
+	return defaultEquality
+}
+
+/*
+ * compiled regex of a pattern known only at evaluation time
+ */
+func (env *Env) regex(pattern string, fold bool) (*regexp.Regexp, error) {
+	if env.parser == nil || env.parser.regexes == nil {
+		return CompileRegex(pattern, POSIX, fold)
+	}
+	h := env.parser
+	return h.regexes.get(normalize(h.normalization, pattern), h.regexSyntax, fold, h.limits.maxRegexSize)
+}
+
+/*
+ * spend one evaluation step, fails once the step budget is used up or
+ * the context is done
+ */
+func (env *Env) step() error {
+	env.steps += 1
+	if env.parser != nil && env.parser.maxSteps > 0 && env.steps > env.parser.maxSteps {
+		return &BudgetExceeded{"steps", env.parser.maxSteps}
+	}
+	if done := env.ctx.Done(); done != nil {
+		select {
+		case <-done:
+			return env.ctx.Err()
+		default:
+		}
+	}
+	return nil
+}
+
+/*
+ * spend one step and go one level deeper, every successful enter() must
+ * be paired with a leave()
+ */
+func (env *Env) enter() error {
+	if err := env.step(); err != nil {
+		return err
+	}
+	if env.parser != nil && env.parser.maxDepth > 0 && env.depth >= env.parser.maxDepth {
+		return &BudgetExceeded{"depth", env.parser.maxDepth}
+	}
+	env.depth += 1
+	return nil
+}
+
+func (env *Env) leave() {
+	env.depth -= 1
+}
+
+func (env *Env) checkSize(size int) error {
+	if env.parser != nil && env.parser.maxValueSize > 0 && size > env.parser.maxValueSize {
+		return &BudgetExceeded{"value size", env.parser.maxValueSize}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+type QKind_t int // for numeric equality
+
+const (
+	ABSOLUTE = QKind_t(0) // equal within epsilon
+	RELATIVE = QKind_t(1) // equal within epsilon times the larger magnitude
+	EXACT    = QKind_t(2) // equal only when the values are the same
+	DECIMAL  = QKind_t(3) // equal when the same rounded to n decimal places
+)
+
+func qkind2str(kind QKind_t) string {
+	switch kind {
+	case ABSOLUTE:
+		return "absolute"
+	case RELATIVE:
+		return "relative"
+	case EXACT:
+		return "exact"
+	case DECIMAL:
+		return "decimal"
+	}
+	return fmt.Sprintf("%d", int(kind))
+}
+
+/*
+ * how doubles, and integers mixed with doubles, are compared; integers
+ * compare exactly with each other whatever the mode
+ */
+type equality struct {
+	kind      QKind_t
+	tolerance float64 // epsilon for ABSOLUTE and RELATIVE, places for DECIMAL
+	scale     *big.Int
+	legacy    bool // compare as before WithNumericEquality() existed
+}
+
+/*
+ * without WithNumericEquality(): ==, >= and <= take values less than
+ * 0.001 apart as equal, != values more than 0.001 apart as unequal, >
+ * and < are strict
+ */
+var defaultEquality = &equality{kind: ABSOLUTE, tolerance: 0.001, legacy: true}
+
+func newEquality(kind QKind_t, tolerance float64) (*equality, error) {
+	q := &equality{kind: kind, tolerance: tolerance}
+	switch kind {
+	case ABSOLUTE, RELATIVE:
+		if !(tolerance >= 0) || math.IsInf(tolerance, 0) {
+			return nil, errors.New(fmt.Sprintf("%s epsilon %v should be a finite number >= 0", qkind2str(kind), tolerance))
+		}
+	case EXACT:
+	case DECIMAL:
+		if tolerance != math.Trunc(tolerance) || tolerance < 0 || tolerance > 18 {
+			return nil, errors.New(fmt.Sprintf("decimal places %v should be an integer from 0 to 18", tolerance))
+		}
+		q.scale = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tolerance)), nil)
+	default:
+		return nil, errors.New(fmt.Sprintf("numeric equality '%s' not supported", qkind2str(kind)))
+	}
+	return q, nil
+}
+
+/*
+ * compare two numbers, at least one of them a double, with operator kind
+ */
+func (q *equality) cmp(kind TKind_t, x, y *Factor) (int, error) {
+	d1, err := cast2number(x)
+	if err != nil {
+		return -1, err
+	}
+	d2, err := cast2number(y)
+	if err != nil {
+		return -1, err
+	}
+	if math.IsNaN(d1) || math.IsNaN(d2) {
+		// NaN is neither less, greater nor equal to anything
+		switch kind {
+		case GT, LT, EQ, GE, LE:
+			return 0, nil
+		case NE:
+			return 1, nil
+		}
+		return -1, errors.New(fmt.Sprintf("double operator '%s' not supported", tkind2str(kind)))
+	}
+
+	if q.legacy {
+		near := d1 == d2 || math.Abs(d1-d2) < q.tolerance
+		switch kind {
+		case GT:
+			return bool2int(d1 > d2), nil
+		case LT:
+			return bool2int(d1 < d2), nil
+		case EQ:
+			return bool2int(near), nil
+		case NE:
+			return bool2int(math.Abs(d1-d2) > q.tolerance), nil
+		case GE:
+			return bool2int(d1 > d2 || near), nil
+		case LE:
+			return bool2int(d1 < d2 || near), nil
+		}
+		return -1, errors.New(fmt.Sprintf("double operator '%s' not supported", tkind2str(kind)))
+	}
+
+	var sign int
+	switch {
+	case d1 == d2 && q.kind != EXACT && q.kind != DECIMAL:
+		sign = 0
+	case q.kind == ABSOLUTE && math.Abs(d1-d2) <= q.tolerance:
+		sign = 0
+	case q.kind == RELATIVE && math.Abs(d1-d2) <= q.tolerance*math.Max(math.Abs(d1), math.Abs(d2)):
+		sign = 0
+	case q.kind == EXACT:
+		sign = exactFloat(x, d1).Cmp(exactFloat(y, d2))
+	case q.kind == DECIMAL && !math.IsInf(d1, 0) && !math.IsInf(d2, 0):
+		sign = q.round(x, d1).Cmp(q.round(y, d2))
+	case d1 < d2:
+		sign = -1
+	case d1 > d2:
+		sign = 1
+	}
+	return cmpSign(kind, sign)
+}
+
+/*
+ * the value of an integer or double without rounding
+ */
+func exactFloat(f *Factor, d float64) *big.Float {
+	if v, ok := f.Value.(int64); ok {
+		return new(big.Float).SetInt64(v)
+	}
+	return big.NewFloat(d)
+}
+
+/*
+ * the value in units of the last decimal place, a double is taken as the
+ * shortest decimal that reads back as it (0.1 is 0.1, not the binary
+ * value just above it) and rounded half away from zero
+ */
+func (q *equality) round(f *Factor, d float64) *big.Int {
+	r := new(big.Rat)
+	if v, ok := f.Value.(int64); ok {
+		r.SetInt64(v)
+	} else {
+		r.SetString(strconv.FormatFloat(d, 'g', -1, 64))
+	}
+	r.Mul(r, new(big.Rat).SetInt(q.scale))
+
+	n, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 && new(big.Int).Abs(new(big.Int).Lsh(rem, 1)).Cmp(r.Denom()) >= 0 {
+		n.Add(n, big.NewInt(int64(rem.Sign())))
+	}
+	return n
+}
+
+/*
+ * the result of operator kind given x is less (-1), equal (0) or greater
+ * (1) than y, so that > and <= (< and >=, == and !=) never agree
+ */
+func cmpSign(kind TKind_t, sign int) (int, error) {
+	switch kind {
+	case GT:
+		return bool2int(sign > 0), nil
+	case LT:
+		return bool2int(sign < 0), nil
+	case EQ:
+		return bool2int(sign == 0), nil
+	case NE:
+		return bool2int(sign != 0), nil
+	case GE:
+		return bool2int(sign >= 0), nil
+	case LE:
+		return bool2int(sign <= 0), nil
+	}
+	return -1, errors.New(fmt.Sprintf("double operator '%s' not supported", tkind2str(kind)))
+}
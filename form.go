@@ -0,0 +1,266 @@
+package filter
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strings"
+)
+
+/*
+ * limits of a decoded request body, 0 means unlimited
+ */
+type FormLimits struct {
+	MaxBodySize  int64  // bytes of the whole body
+	MaxFieldSize int64  // bytes of the value of an ordinary field
+	MaxFileSize  int64  // bytes of the content of a file part
+	MaxParts     int    // fields and files
+	Hash         string // digest of file content: "", "md5", "sha1" or "sha256"
+
+	Xml XmlLimits // of XML bodies
+}
+
+var DefaultFormLimits = FormLimits{
+	MaxBodySize:  32 << 20,
+	MaxFieldSize: 1 << 20,
+	MaxFileSize:  32 << 20,
+	MaxParts:     1000,
+	Xml:          DefaultXmlLimits,
+}
+
+/*
+ * parse an application/x-www-form-urlencoded body to symlist:
+ * name=bob&note=hello+world&tag=a&tag=b
+ * names and values are unescaped ('+' is a space), a name without '='
+ * has the value ''; a name given more than once is a query key given more
+ * than once (see QueryToSymlist())
+ */
+func FormToSymlist(body string) (symlist *SymList, err error) {
+	for _, pair := range strings.Split(body, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value := pair, ""
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			name, value = pair[:i], pair[i+1:]
+		}
+		if name, err = url.QueryUnescape(name); err != nil {
+			return nil, err
+		}
+		if value, err = url.QueryUnescape(value); err != nil {
+			return nil, err
+		}
+		symlist = appendSymlistValue(symlist, name, value)
+	}
+	return symlist, nil
+}
+
+/*
+ * parse a multipart/form-data body to symlist, contentType is the value
+ * of the Content-Type header holding the boundary. ordinary fields are
+ * strings as in FormToSymlist(); the first file of a field 'upload' is
+ * described by:
+ *	upload.filename		name of the file as sent by the client
+ *	upload.content_type	content type of the part
+ *	upload.size		bytes of content (integer)
+ *	upload.hash		hex digest of content, if limits.Hash is set
+ * and upload.files is an array of all files of the field, each an object
+ * with the same fields. file content is read but not kept
+ */
+func MultipartToSymlist(body io.Reader, contentType string, limits FormLimits) (*SymList, error) {
+	mediatype, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	if mediatype != "multipart/form-data" || params["boundary"] == "" {
+		return nil, errors.New(fmt.Sprintf("'%s' is not multipart/form-data with a boundary", contentType))
+	}
+	if limits.Hash != "" && newFormHash(limits.Hash) == nil {
+		return nil, errors.New(fmt.Sprintf("form hash '%s' not supported", limits.Hash))
+	}
+
+	if limits.MaxBodySize > 0 {
+		body = &limitedReader{body, limits.MaxBodySize, limits.MaxBodySize, "body"}
+	}
+	reader := multipart.NewReader(body, params["boundary"])
+
+	var symlist *SymList
+	files := map[string][]*SymList{}
+	names := []string{}
+	for n := 0; ; n++ {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if limits.MaxParts > 0 && n >= limits.MaxParts {
+			return nil, errors.New(fmt.Sprintf("form has more than %d parts", limits.MaxParts))
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		if part.FileName() == "" {
+			value, err := readPart(part, limits.MaxFieldSize, "field '"+name+"'")
+			if err != nil {
+				return nil, err
+			}
+			symlist = appendSymlistValue(symlist, name, string(value))
+			continue
+		}
+
+		file, err := describeFile(part, limits)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := files[name]; !ok {
+			names = append(names, name)
+			for p := file; p != nil; p = p.Next {
+				symlist = appendSymbol(symlist, name+"."+p.Name, p)
+			}
+		}
+		files[name] = append(files[name], file)
+	}
+
+	for _, name := range names {
+		if symlist == nil {
+			symlist, _ = NewSymlistArray(name+".files", files[name])
+		} else {
+			symlist, _ = AppendSymlistArray(symlist, name+".files", files[name])
+		}
+	}
+	return symlist, nil
+}
+
+/*
+ * decode a request body by its Content-Type: JSON, XML, urlencoded form
+ * or multipart form
+ */
+func BodyToSymlist(body io.Reader, contentType string, limits FormLimits) (*SymList, error) {
+	mediatype, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	switch mediatype {
+	case "multipart/form-data":
+		return MultipartToSymlist(body, contentType, limits)
+	case "application/x-www-form-urlencoded", "application/json":
+	case "application/xml", "text/xml", "application/soap+xml":
+	default:
+		return nil, errors.New(fmt.Sprintf("content type '%s' not supported", mediatype))
+	}
+
+	data, err := readPart(body, limits.MaxBodySize, "body")
+	if err != nil {
+		return nil, err
+	}
+	switch mediatype {
+	case "application/json":
+		return JsonToSymlist(string(data))
+	case "application/x-www-form-urlencoded":
+		return FormToSymlist(string(data))
+	}
+	return XmlToSymlist(string(data), limits.Xml)
+}
+
+/*
+ * the symbols of a file part: filename, content_type, size and hash
+ */
+func describeFile(part *multipart.Part, limits FormLimits) (*SymList, error) {
+	var h hash.Hash
+	var w io.Writer = ioutil.Discard
+	if limits.Hash != "" {
+		h = newFormHash(limits.Hash)
+		w = h
+	}
+	var r io.Reader = part
+	if limits.MaxFileSize > 0 {
+		r = &limitedReader{part, limits.MaxFileSize, limits.MaxFileSize, "file '" + part.FileName() + "'"}
+	}
+	size, err := io.Copy(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	file, _ := NewSymlistString("filename", part.FileName())
+	file, _ = AppendSymlistString(file, "content_type", part.Header.Get("Content-Type"))
+	file, _ = AppendSymlistInteger(file, "size", size)
+	if h != nil {
+		file, _ = AppendSymlistString(file, "hash", hex.EncodeToString(h.Sum(nil)))
+	}
+	return file, nil
+}
+
+func newFormHash(name string) hash.Hash {
+	switch name {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha256":
+		return sha256.New()
+	}
+	return nil
+}
+
+/*
+ * append a copy of symbol s named name, unless name is already there
+ */
+func appendSymbol(symlist *SymList, name string, s *SymList) *SymList {
+	symbol := &SymList{Kind: s.Kind, Name: name, Value: s.Value}
+	if symlist == nil {
+		return symbol
+	}
+	pre := symlist
+	for p := symlist; p != nil; p = p.Next {
+		if name == p.Name {
+			return symlist
+		}
+		pre = p
+	}
+	pre.Next = symbol
+	return symlist
+}
+
+func readPart(r io.Reader, max int64, what string) ([]byte, error) {
+	if max > 0 {
+		r = &limitedReader{r, max, max, what}
+	}
+	return ioutil.ReadAll(r)
+}
+
+/*
+ * a reader failing once more than n bytes are read
+ */
+type limitedReader struct {
+	r    io.Reader
+	n    int64
+	max  int64
+	what string
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errors.New(fmt.Sprintf("%s larger than %d bytes", l.what, l.max))
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return 0, errors.New(fmt.Sprintf("%s larger than %d bytes", l.what, l.max))
+	}
+	return n, err
+}
@@ -0,0 +1,133 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+/*
+ * compile a shell style wildcard into an anchored regex, the whole value
+ * must match:
+ *	*	any characters except '/'
+ *	?	one character except '/'
+ *	[...]	one character in the class, [!...] or [^...] negates it;
+ *		'/' never matches
+ *	**	as a whole path segment (between slashes or at either end of
+ *		the pattern) zero or more segments, anywhere else any
+ *		characters including '/'
+ *	\x	the character x itself
+ */
+func CompileGlob(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 >= len(pattern) || pattern[i+1] != '*' {
+				b.WriteString("[^/]*")
+				break
+			}
+			segment := i == 0 || pattern[i-1] == '/'
+			i += 1
+			if segment && i+1 < len(pattern) && pattern[i+1] == '/' {
+				b.WriteString("(?:.*/)?")
+				i += 1
+			} else {
+				b.WriteString(".*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			class, n, err := globClass(pattern[i:])
+			if err != nil {
+				return nil, err
+			}
+			b.WriteString(class)
+			i += n - 1
+		case '\\':
+			if i+1 >= len(pattern) {
+				return nil, errors.New(fmt.Sprintf("glob '%s' ends with '\\'", pattern))
+			}
+			i += 1
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+/*
+ * translate the class at the start of pattern, return the regex class
+ * and the number of bytes of pattern it took
+ */
+func globClass(pattern string) (string, int, error) {
+	var b strings.Builder
+	b.WriteString("[")
+	i := 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		b.WriteString("^")
+		i += 1
+	}
+	// a ']' right after the opening is taken literally
+	for first := true; i < len(pattern); i, first = i+1, false {
+		c := pattern[i]
+		switch {
+		case c == ']' && !first:
+			b.WriteString("]")
+			class, err := dropSlash(b.String())
+			return class, i + 1, err
+		case c == '\\' && i+1 < len(pattern):
+			i += 1
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case c == '[' || c == ']' || c == '^' || c == '\\':
+			b.WriteString("\\" + string(c))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, errors.New(fmt.Sprintf("glob '%s' has unclosed '['", pattern))
+}
+
+/*
+ * remove '/' from a regex class, even when it is listed or in a range
+ */
+func dropSlash(class string) (string, error) {
+	re, err := syntax.Parse(class, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	var ranges []rune
+	switch re.Op {
+	case syntax.OpCharClass:
+		ranges = re.Rune
+	case syntax.OpLiteral:
+		ranges = []rune{re.Rune[0], re.Rune[0]}
+	default:
+		return class, nil
+	}
+
+	re = &syntax.Regexp{Op: syntax.OpCharClass}
+	for j := 0; j < len(ranges); j += 2 {
+		lo, hi := ranges[j], ranges[j+1]
+		if hi < '/' || lo > '/' {
+			re.Rune = append(re.Rune, lo, hi)
+			continue
+		}
+		if lo < '/' {
+			re.Rune = append(re.Rune, lo, '/'-1)
+		}
+		if hi > '/' {
+			re.Rune = append(re.Rune, '/'+1, hi)
+		}
+	}
+	if len(re.Rune) == 0 {
+		re.Op = syntax.OpNoMatch
+	}
+	return re.String(), nil
+}
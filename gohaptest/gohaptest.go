@@ -0,0 +1,256 @@
+/*
+ * package gohaptest runs corpora of filter rules and their expected values
+ * under go test. a sample file holds one case per line:
+ *
+ *	expect%rule%input
+ *
+ * expect is the value the rule returns for input, 'error' if compiling or
+ * evaluating the rule fails, 'error:text' if it fails with a message
+ * containing text. a literal '%' in the rule or the input is written '%%'.
+ * the input is 'json:' followed by a JSON object, 'query:' followed by a
+ * query string or 'form:' followed by an urlencoded form; without prefix
+ * it is JSON if it starts with '{', else a query string. '@name' is the
+ * input defined before by a line, taken as it is (no '%%' escaping)
+ *
+ *	@name input
+ *
+ * empty lines and lines starting with '//' are skipped. files ending in
+ * '.rules' are rule files (see filter.LoadRuleFile()) whose @expect cases
+ * are run
+ */
+package gohaptest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	filter "github.com/soforth/gohap"
+)
+
+/*
+ * a line of a sample file
+ */
+type Case struct {
+	File      string
+	Line      int
+	Expect    int
+	WantError bool   // the rule fails, Expect is ignored
+	ErrorText string // the message of the failure contains it
+	Rule      string
+	Format    string // "json", "query" or "form"
+	Input     string
+}
+
+var inputRegex = regexp.MustCompile(`^@([_a-zA-Z][_a-zA-Z0-9]*)[ \t]+(.*)$`)
+
+/*
+ * run the cases of the named files of fsys, each file as a subtest of t
+ * named by the file. every failed case is reported with its file:line
+ */
+func Run(t *testing.T, fsys fs.FS, names []string, opts ...filter.Option) {
+	t.Helper()
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			t.Helper()
+			RunFile(t, fsys, name, opts...)
+		})
+	}
+}
+
+/*
+ * run the cases of the file name of fsys, failures of sample and rule
+ * files alike are reported as 'file:line: ...'
+ */
+func RunFile(t testing.TB, fsys fs.FS, name string, opts ...filter.Option) {
+	t.Helper()
+	if strings.HasSuffix(name, ".rules") {
+		specs, err := filter.LoadRuleFile(fsys, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, spec := range specs {
+			for _, err := range spec.RunTests(opts...) {
+				f := err.(*filter.TestFailure)
+				if f.Err != nil {
+					t.Errorf("%s:%d: rule '%s': %v", f.File, f.Line, f.Id, f.Err)
+				} else {
+					t.Errorf("%s:%d: rule '%s': expect %d, actual %d", f.File, f.Line, f.Id, f.Expect, f.Actual)
+				}
+			}
+		}
+		return
+	}
+
+	cases, err := Load(fsys, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		if err := c.Run(opts...); err != nil {
+			t.Errorf("%s:%d: %v", c.File, c.Line, err)
+		}
+	}
+}
+
+/*
+ * the cases of the sample file name of fsys
+ */
+func Load(fsys fs.FS, name string) ([]*Case, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(name, f)
+}
+
+/*
+ * the cases of a sample file read from in, name is the file name of the
+ * cases and errors
+ */
+func Parse(name string, in io.Reader) ([]*Case, error) {
+	cases := []*Case{}
+	inputs := map[string]*Case{}
+	fail := func(line int, format string, a ...interface{}) error {
+		return errors.New(fmt.Sprintf("%s:%d: %s", name, line, fmt.Sprintf(format, a...)))
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if strings.HasPrefix(line, "@") {
+			m := inputRegex.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fail(n, "input definition is '@name input'")
+			}
+			c := &Case{File: name, Line: n}
+			c.Format, c.Input = inputFormat(m[2])
+			inputs[m[1]] = c
+			continue
+		}
+
+		fields := splitCase(line)
+		if len(fields) != 3 {
+			return nil, fail(n, "%d fields, expect 3 (expect%%rule%%input), a '%%' is written '%%%%'", len(fields))
+		}
+		c := &Case{File: name, Line: n, Rule: fields[1]}
+		expect := strings.TrimSpace(fields[0])
+		if expect == "error" || strings.HasPrefix(expect, "error:") {
+			c.WantError = true
+			c.ErrorText = strings.TrimPrefix(strings.TrimPrefix(expect, "error"), ":")
+		} else if v, err := strconv.Atoi(expect); err == nil {
+			c.Expect = v
+		} else {
+			return nil, fail(n, "expect '%s' is no integer or error", expect)
+		}
+		if strings.HasPrefix(fields[2], "@") {
+			input, ok := inputs[fields[2][1:]]
+			if !ok {
+				return nil, fail(n, "input '%s' not defined", fields[2])
+			}
+			c.Format, c.Input = input.Format, input.Input
+		} else {
+			c.Format, c.Input = inputFormat(fields[2])
+		}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+/*
+ * the fields of a case separated by '%', '%%' being a '%' in a field
+ */
+func splitCase(line string) []string {
+	fields := []string{}
+	var field strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] != '%' {
+			field.WriteByte(line[i])
+		} else if i+1 < len(line) && line[i+1] == '%' {
+			field.WriteByte('%')
+			i += 1
+		} else {
+			fields = append(fields, field.String())
+			field.Reset()
+		}
+	}
+	return append(fields, field.String())
+}
+
+/*
+ * the format of an input and the input without its prefix
+ */
+func inputFormat(input string) (string, string) {
+	for _, format := range []string{"json", "query", "form"} {
+		if strings.HasPrefix(input, format+":") {
+			return format, input[len(format)+1:]
+		}
+	}
+	if strings.HasPrefix(input, "{") {
+		return "json", input
+	}
+	return "query", input
+}
+
+/*
+ * the symlist of the input of the case
+ */
+func (c *Case) Symlist() (*filter.SymList, error) {
+	switch c.Format {
+	case "json":
+		return filter.JsonToSymlist(c.Input)
+	case "form":
+		return filter.FormToSymlist(c.Input)
+	}
+	return filter.QueryToSymlist(c.Input)
+}
+
+/*
+ * evaluate the case, nil if the rule returns what it expects. an input
+ * that can't be read fails the rule
+ */
+func (c *Case) Run(opts ...filter.Option) error {
+	actual, err := c.eval(opts)
+	if !c.WantError {
+		if err != nil {
+			return err
+		}
+		if actual != c.Expect {
+			return errors.New(fmt.Sprintf("expect %d, actual %d", c.Expect, actual))
+		}
+		return nil
+	}
+	if err == nil {
+		return errors.New(fmt.Sprintf("expect an error, actual %d", actual))
+	}
+	if !strings.Contains(err.Error(), c.ErrorText) {
+		return errors.New(fmt.Sprintf("expect an error with '%s', actual: %v", c.ErrorText, err))
+	}
+	return nil
+}
+
+func (c *Case) eval(opts []filter.Option) (int, error) {
+	h, err := filter.NewParser(strings.NewReader(c.Rule), opts...)
+	if err != nil {
+		return 0, err
+	}
+	symlist, err := c.Symlist()
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("input: %v", err))
+	}
+	return h.Parse(symlist)
+}
@@ -0,0 +1,129 @@
+package gohaptest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	filter "github.com/soforth/gohap"
+)
+
+func TestParse(t *testing.T) {
+	src := `// comment
+
+@order {"total": 120, "items": [{"price": 20}, {"price": 100}]}
+@login form:user=a%20b&pass=x
+1%x == '100%%'%x=100%%
+1%total > 100 && size(items) == 2%@order
+1%user == 'a b'%@login
+0%user == 'a%%20b'%@login
+1%x == 'a+b'%query:x=a+b
+1%x == 'a b'%form:x=a+b
+error%x == 1%{"x":
+error:not found%y == 1%x=1
+error%x ==%x=1
+`
+	cases, err := Parse("t", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 9 {
+		t.Fatalf("%d cases, expect 9", len(cases))
+	}
+	if c := cases[0]; c.Line != 5 || c.Rule != "x == '100%'" || c.Format != "query" || c.Input != "x=100%" {
+		t.Errorf("%+v", c)
+	}
+	if c := cases[2]; c.Format != "form" || c.Input != "user=a%20b&pass=x" {
+		t.Errorf("%+v", c)
+	}
+	if c := cases[7]; !c.WantError || c.ErrorText != "not found" {
+		t.Errorf("%+v", c)
+	}
+	for _, c := range cases {
+		if err := c.Run(); err != nil {
+			t.Errorf("%s:%d: %v", c.File, c.Line, err)
+		}
+	}
+
+	// cases failing the way they don't expect
+	failing := map[string]string{
+		"1%x == '1'%x=2":              "expect 1, actual 0",
+		"1%x == %x=1":                 "syntax error",
+		"error%x == '1'%x=1":          "expect an error, actual 1",
+		"error:syntax%y == '1'%x=1":   "expect an error with 'syntax'",
+		"1%x == 1%x=1":                "expect 1, actual 0",
+		"1%x == 1%{\"x\":1}":          "",
+		"2%x > 1 => 2%json:{\"x\":3}": "",
+	}
+	for line, expect := range failing {
+		cases, err := Parse("t", strings.NewReader(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = cases[0].Run()
+		if (err == nil) != (expect == "") || err != nil && !strings.Contains(err.Error(), expect) {
+			t.Errorf("%s: %v, expect '%s'", line, err, expect)
+		}
+	}
+
+	malformed := map[string]string{
+		"1%x == 1":        "t:1: 2 fields",
+		"1%x%y%z":         "t:1: 4 fields",
+		"yes%x == 1%x=1":  "t:1: expect 'yes'",
+		"1%x == 1%@none":  "t:1: input '@none' not defined",
+		"// c\n@in":       "t:2: input definition",
+		"1%x%%y%x=1%%%zz": "t:1: 4 fields",
+	}
+	for src, expect := range malformed {
+		_, err := Parse("t", strings.NewReader(src))
+		if err == nil || !strings.HasPrefix(err.Error(), expect) {
+			t.Errorf("%q: %v, expect '%s'", src, err, expect)
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"coerce":  {Data: []byte("1%x == 10%x=10\n")},
+		"t.rules": {Data: []byte("@id a\n@expect 1 x=10\nx == 10\n")},
+	}
+	RunFile(t, fsys, "coerce", filter.WithCoercion(filter.NUMERIC))
+	RunFile(t, fsys, "t.rules", filter.WithCoercion(filter.NUMERIC))
+	Run(t, fsys, []string{"coerce", "t.rules"}, filter.WithCoercion(filter.NUMERIC))
+}
+
+/*
+ * a testing.TB keeping the failures reported to it
+ */
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestReport(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cases":   {Data: []byte("// c\n1%x == 1%x=2\n")},
+		"t.rules": {Data: []byte("@id a\n@expect 1 x=10\n@expect 1 x=1\nx == '1'\n\n@id b\n@expect 1\nx ==\n")},
+	}
+	r := &recorder{TB: t}
+	RunFile(r, fsys, "cases")
+	RunFile(r, fsys, "t.rules")
+	expect := []string{"cases:2: expect 1, actual 0", "t.rules:2: rule 'a': expect 1, actual 0", "t.rules:6: rule 'b': "}
+	if len(r.errors) != len(expect) {
+		t.Fatalf("%q, expect %q", r.errors, expect)
+	}
+	for i, e := range expect {
+		if !strings.HasPrefix(r.errors[i], e) {
+			t.Errorf("%s, expect %s", r.errors[i], e)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package filter
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net/url"
+)
+
+/*
+ * concatenate all string arguments of a builtin, the same way md5() does:
+ * string constants, variables and function results are joined in order
+ */
+func EvalConcat(name string, list *List, env *Env) (string, error) {
+	deferr := errors.New(fmt.Sprintf("%s() parameter should be 'string'", name))
+	s := ""
+	for p := list; p != nil; p = p.Next {
+		if err := env.step(); err != nil {
+			return "", err
+		}
+		switch p.Factor.Kind {
+		case STRING:
+			if v, err := cast2string(p.Factor.Value); err != nil {
+				return "", err
+			} else {
+				s += v
+			}
+		case VARIABLE:
+			if v, err := cast2string(p.Factor.Value); err != nil {
+				return "", err
+			} else {
+				var value *Factor
+				value, err := env.Lookup(v)
+				if err != nil {
+					return "", err
+				}
+				if value.Kind != STRING && !isNumber(value) {
+					return "", deferr
+				}
+				if v2, err := env.asString(name, value); err != nil {
+					return "", err
+				} else {
+					s += v2
+				}
+			}
+		case FUNCTION:
+			if v, err := cast2func(p.Factor.Value); err != nil {
+				return "", err
+			} else {
+				var value *Factor
+				if value, err = EvalFunc(v, env); err != nil {
+					return "", err
+				}
+				if value.Kind != STRING && !isNumber(value) {
+					return "", deferr
+				}
+				if v2, err := env.asString(name, value); err != nil {
+					return "", err
+				} else {
+					s += v2
+				}
+			}
+		default:
+			// a number constant is formatted like a number variable
+			if v, err := env.asString(name, p.Factor); err != nil {
+				return "", err
+			} else {
+				s += v
+			}
+		}
+		if err := env.checkSize(len(s)); err != nil {
+			return "", err
+		}
+	}
+	return s, nil
+}
+
+/*
+ * sha1(), sha256(), sha512(): hex digest of the concatenated arguments
+ * crc32(): IEEE checksum of the concatenated arguments as 8 hex digits
+ */
+func EvalHash(kind FnKind_t, list *List, env *Env) (*Factor, error) {
+	name := fnkind2str(kind)
+	if list == nil || list.Factor == nil {
+		return nil, errors.New(fmt.Sprintf("%s() with invalid parameter", name))
+	}
+
+	v, err := EvalConcat(name, list, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case SHA1:
+		return NewFactor(STRING, 0, fmt.Sprintf("%x", sha1.Sum([]byte(v))), "", nil)
+	case SHA256:
+		return NewFactor(STRING, 0, fmt.Sprintf("%x", sha256.Sum256([]byte(v))), "", nil)
+	case SHA512:
+		return NewFactor(STRING, 0, fmt.Sprintf("%x", sha512.Sum512([]byte(v))), "", nil)
+	case CRC32:
+		return NewFactor(STRING, 0, fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(v))), "", nil)
+	}
+
+	return nil, errors.New(fmt.Sprintf("hash '%s' not supported", name))
+}
+
+/*
+ * hmac_sha256(key, msg...): the first argument is the key, the rest are
+ * concatenated as the message
+ */
+func EvalHmac(list *List, env *Env) (*Factor, error) {
+	if list == nil || list.Factor == nil || list.Next == nil {
+		return nil, errors.New("hmac_sha256() with invalid parameter")
+	}
+
+	key, err := EvalConcat("hmac_sha256", &List{Factor: list.Factor}, env)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := EvalConcat("hmac_sha256", list.Next, env)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(msg))
+	return NewFactor(STRING, 0, fmt.Sprintf("%x", mac.Sum(nil)), "", nil)
+}
+
+/*
+ * base64/hex/url encode and decode of the concatenated arguments
+ */
+func EvalCodec(kind FnKind_t, list *List, env *Env) (*Factor, error) {
+	name := fnkind2str(kind)
+	if list == nil || list.Factor == nil {
+		return nil, errors.New(fmt.Sprintf("%s() with invalid parameter", name))
+	}
+
+	v, err := EvalConcat(name, list, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case BASE64_ENCODE:
+		return NewFactor(STRING, 0, base64.StdEncoding.EncodeToString([]byte(v)), "", nil)
+	case BASE64_DECODE:
+		if b, err := base64.StdEncoding.DecodeString(v); err != nil {
+			return nil, err
+		} else {
+			return NewFactor(STRING, 0, string(b), "", nil)
+		}
+	case HEX_ENCODE:
+		return NewFactor(STRING, 0, hex.EncodeToString([]byte(v)), "", nil)
+	case HEX_DECODE:
+		if b, err := hex.DecodeString(v); err != nil {
+			return nil, err
+		} else {
+			return NewFactor(STRING, 0, string(b), "", nil)
+		}
+	case URL_ENCODE:
+		return NewFactor(STRING, 0, url.QueryEscape(v), "", nil)
+	case URL_DECODE:
+		if s, err := url.QueryUnescape(v); err != nil {
+			return nil, err
+		} else {
+			return NewFactor(STRING, 0, s, "", nil)
+		}
+	}
+
+	return nil, errors.New(fmt.Sprintf("codec '%s' not supported", name))
+}
+
+/*
+ * secure_eq(a, b): 1 if a and b are equal, compared in constant time so
+ * signature checks don't leak how many leading bytes matched
+ */
+func EvalSecureEq(list *List, env *Env) (*Factor, error) {
+	if list == nil || list.Factor == nil || list.Next == nil || list.Next.Next != nil {
+		return nil, errors.New("secure_eq() with invalid parameter")
+	}
+
+	a, err := EvalConcat("secure_eq", &List{Factor: list.Factor}, env)
+	if err != nil {
+		return nil, err
+	}
+	b, err := EvalConcat("secure_eq", list.Next, env)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+	return NewIntegerFactor(int64(bool2int(rc)))
+}
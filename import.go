@@ -0,0 +1,92 @@
+package filter
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var namespaceRegex = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+/*
+ * import 'file' [as ns]: the declarations of file are visible as ns.name,
+ * ns defaults to the file name without directory and extension
+ * include 'file': the declarations of file are visible as they are
+ *
+ * files are read from the resolver set by WithResolver(), paths are
+ * relative to the importing file; imported files hold declarations only
+ */
+func (l *ruleLexer) load(name, ns string, include bool) {
+	file := name
+	if l.file != "" {
+		file = path.Join(path.Dir(l.file), name)
+	}
+	if l.parser.resolver == nil {
+		l.fail(fmt.Sprintf("'%s' can't be imported without a resolver", file))
+	}
+	if !fs.ValidPath(file) {
+		l.fail(fmt.Sprintf("invalid import path '%s'", file))
+	}
+	if !include && ns == "" {
+		ns = strings.TrimSuffix(path.Base(file), path.Ext(file))
+		if !namespaceRegex.MatchString(ns) {
+			l.fail(fmt.Sprintf("'%s' is no valid namespace, use import '%s' as <name>", ns, name))
+		}
+	}
+
+	bindings := l.loadFile(file)
+	for k, b := range bindings {
+		if !include {
+			k = ns + "." + k
+		}
+		l.declare(k, b)
+	}
+}
+
+/*
+ * parse file once per NewParser call and return its declarations
+ */
+func (l *ruleLexer) loadFile(file string) map[string]*Binding {
+	chain := file
+	for p := l; p != nil; p = p.parent {
+		chain = p.file + " -> " + chain
+		if p.file == file {
+			l.fail(fmt.Sprintf("import cycle: %s", strings.TrimPrefix(chain, " -> ")))
+		}
+	}
+	if bindings, ok := l.imported[file]; ok {
+		return bindings
+	}
+
+	src, err := fs.ReadFile(l.parser.resolver, file)
+	if err != nil {
+		l.fail(err.Error())
+	}
+	if max := l.parser.limits.maxSourceSize; max > 0 && len(src) > max {
+		l.fail(fmt.Sprintf("'%s' larger than %d bytes", file, max))
+	}
+
+	child := newRuleLexer(l.parser, NewLexer(bytes.NewReader(src)))
+	child.file = file
+	child.parent = l
+	child.imported = l.imported
+	child.params = l.params
+	func() {
+		// errors within file are reported at their position in file
+		defer func() {
+			if e := recover(); e != nil {
+				panic(child.errorf(e))
+			}
+		}()
+		yyParse(child)
+	}()
+	if child.grammer != nil {
+		l.fail(fmt.Sprintf("'%s' may hold declarations only", file))
+	}
+
+	l.imported[file] = child.bindings
+	return child.bindings
+}
@@ -0,0 +1,103 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+/*
+ * match(x, 'pattern', n): capture group n of the match of pattern in x,
+ * or '' if x doesn't match; n is a group number (0 for the whole match,
+ * the default) or the name of a (?P<name>...) group. pattern and n must
+ * be constants: NewFunc() checks the parameters, CompileMatch() compiles
+ * the pattern once the parser's regex syntax is known
+ */
+func checkMatch(fn *Func) error {
+	list := fn.List
+	if list == nil || list.Next == nil || (list.Next.Next != nil && list.Next.Next.Next != nil) {
+		return errors.New("match() with invalid parameter")
+	}
+	if list.Next.Factor.Kind != STRING {
+		return errors.New("match() pattern should be a 'string' constant")
+	}
+	if list.Next.Next != nil {
+		switch list.Next.Next.Factor.Kind {
+		case DOUBLE, INTEGER, STRING:
+		default:
+			return errors.New("match() group should be a 'double' or 'string' constant")
+		}
+	}
+	return nil
+}
+
+/*
+ * compile the pattern of match() in normal form form: with syntax kind
+ * PERL as the patterns of regex terms are, with RE2 syntax matching as
+ * POSIX does for kind POSIX
+ */
+func CompileMatch(fn *Func, kind RKind_t, form NKind_t) error {
+	if err := checkMatch(fn); err != nil {
+		return err
+	}
+	list := fn.List
+	pattern := normalize(form, list.Next.Factor.Value.(string))
+	var regex *regexp.Regexp
+	var err error
+	if kind == POSIX {
+		// RE2 syntax so groups can be named, matched leftmost longest and
+		// with ^ and $ at line boundaries as the POSIX patterns of # are
+		if regex, err = regexp.Compile("(?m:" + pattern + ")"); err == nil {
+			regex.Longest()
+		}
+	} else {
+		regex, err = CompileRegex(pattern, kind, false)
+	}
+	if err != nil {
+		return err
+	}
+	fn.Regex = regex
+
+	if list.Next.Next == nil {
+		fn.Group = 0
+		return nil
+	}
+	group := list.Next.Next.Factor
+	switch group.Kind {
+	case DOUBLE, INTEGER:
+		v, _ := cast2number(group)
+		n := int(v)
+		if float64(n) != v || n < 0 || n > regex.NumSubexp() {
+			return errors.New(fmt.Sprintf("match() pattern '%s' has no group %v", pattern, group.Value))
+		}
+		fn.Group = n
+	case STRING:
+		n := regex.SubexpIndex(group.Value.(string))
+		if n < 0 {
+			return errors.New(fmt.Sprintf("match() pattern '%s' has no group '%s'", pattern, group.Value))
+		}
+		fn.Group = n
+	}
+	return nil
+}
+
+func EvalMatch(fn *Func, env *Env) (*Factor, error) {
+	if fn.Regex == nil {
+		return nil, errors.New("match() with invalid parameter")
+	}
+
+	value, err := EvalFactor(fn.List.Factor, env)
+	if err != nil {
+		return nil, err
+	}
+	s, err := env.asString("match", value)
+	if err != nil {
+		return nil, err
+	}
+
+	m := fn.Regex.FindStringSubmatch(env.normalize(s))
+	if m == nil {
+		return NewFactor(STRING, 0, "", "", nil)
+	}
+	return NewFactor(STRING, 0, m[fn.Group], "", nil)
+}
@@ -0,0 +1,183 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+ * numbers are integers (int64) or doubles (float64); integer literals,
+ * JSON integers, len(), count() and size() are integers and compare
+ * exactly with each other, a double mixed with an integer makes both
+ * doubles
+ */
+
+/*
+ * token of a numeric literal: 123, -7 and 0xff are INT, 1.5, .5 and 1e6
+ * are NUM; integers out of the int64 range and anything else that
+ * starts like a number (1.2.3, 1e, 0x) are BADNUM
+ */
+func lexNumber(text string, lval *yySymType) int {
+	lval.str = text
+	digits := strings.TrimPrefix(text, "-")
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		v, err := strconv.ParseInt(digits[2:], 16, 64)
+		if err != nil {
+			return BADNUM
+		}
+		if digits != text {
+			v = -v
+		}
+		lval.ival = v
+		return INT
+	}
+	if !strings.ContainsAny(digits, ".eE") {
+		v, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return BADNUM
+		}
+		lval.ival = v
+		return INT
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return BADNUM
+	}
+	lval.dval = v
+	return NUM
+}
+
+func NewIntegerFactor(v int64) (*Factor, error) {
+	f := new(Factor)
+	f.Kind = INTEGER
+	f.Value = v
+	return f, nil
+}
+
+func cast2int64(i interface{}) (int64, error) {
+	if v, ok := i.(int64); ok == true {
+		return v, nil
+	}
+	return 0, errors.New("not a 'int64'")
+}
+
+/*
+ * value of an integer or double factor as float64
+ */
+func cast2number(f *Factor) (float64, error) {
+	switch f.Kind {
+	case INTEGER:
+		v, err := cast2int64(f.Value)
+		return float64(v), err
+	case DOUBLE:
+		return cast2float64(f.Value)
+	}
+	return 0, errors.New(fmt.Sprintf("'%s' is not a number", fkind2str(f.Kind)))
+}
+
+func isNumber(f *Factor) bool {
+	return f.Kind == INTEGER || f.Kind == DOUBLE
+}
+
+/*
+ * a factor of a numeric JSON value, integers are kept exact when they fit
+ */
+func jsonNumber(s string) (FKind_t, interface{}, error) {
+	if !strings.ContainsAny(s, ".eE") {
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return INTEGER, v, nil
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return DOUBLE, v, err
+}
+
+func CmpInt(kind TKind_t, i1, i2 int64) (int, error) {
+	switch kind {
+	case GT:
+		return bool2int(i1 > i2), nil
+	case LT:
+		return bool2int(i1 < i2), nil
+	case EQ:
+		return bool2int(i1 == i2), nil
+	case NE:
+		return bool2int(i1 != i2), nil
+	case GE:
+		return bool2int(i1 >= i2), nil
+	case LE:
+		return bool2int(i1 <= i2), nil
+	}
+	return -1, errors.New(fmt.Sprintf("int operator '%s' not supported", tkind2str(kind)))
+}
+
+/*
+ * text of a number: integers in decimal, doubles with as many digits as
+ * needed (100, 0.5, 1e+21); format is a fmt verb such as '%05d', '%x'
+ * or '%.2f', converting the number to the verb's type
+ */
+func formatNumber(value *Factor, format string) (string, error) {
+	if format == "" {
+		switch value.Kind {
+		case INTEGER:
+			v, err := cast2int64(value.Value)
+			return strconv.FormatInt(v, 10), err
+		case DOUBLE:
+			v, err := cast2float64(value.Value)
+			return strconv.FormatFloat(v, 'g', -1, 64), err
+		}
+		return "", errors.New("itoa() parameter should be 'double'")
+	}
+
+	v, err := cast2number(value)
+	if err != nil {
+		return "", errors.New("itoa() parameter should be 'double'")
+	}
+	if strings.Count(format, "%") != 1 || !strings.HasPrefix(format, "%") {
+		return "", errors.New(fmt.Sprintf("itoa() format '%s' should be a single verb", format))
+	}
+	switch format[len(format)-1] {
+	case 'd', 'x', 'X', 'o', 'b':
+		if value.Kind == INTEGER {
+			return fmt.Sprintf(format, value.Value.(int64)), nil
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", errors.New(fmt.Sprintf("itoa() can't format %v with '%s'", v, format))
+		}
+		return fmt.Sprintf(format, int64(v)), nil
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		return fmt.Sprintf(format, v), nil
+	}
+	return "", errors.New(fmt.Sprintf("itoa() format '%s' should be a single verb", format))
+}
+
+/*
+ * number of the text s: an integer if s is one, a double otherwise
+ */
+func atoiFactor(s string) (*Factor, error) {
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return NewIntegerFactor(v)
+	}
+	if dbl, err := strconv.ParseFloat(s, 64); err != nil {
+		return nil, err
+	} else {
+		return NewFactor(DOUBLE, dbl, "", "", nil)
+	}
+}
+
+/*
+ * atoi() of s: the empty string, which match() gives when nothing
+ * matches, is 0 as for C's atoi(); other strings must be numbers
+ */
+func atoiString(s string) (*Factor, error) {
+	if s == "" {
+		return NewIntegerFactor(0)
+	}
+	if v, err := atoiFactor(s); err != nil {
+		return nil, errors.New(fmt.Sprintf("atoi() parameter '%s' is no number", s))
+	} else {
+		return v, nil
+	}
+}
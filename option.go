@@ -0,0 +1,195 @@
+package filter
+
+import (
+	"io/fs"
+	"time"
+)
+
+/*
+ * an option tunes a parser created by NewParser
+ */
+type Option func(h *Parser)
+
+/*
+ * use clock instead of time.Now() for the time builtins, so tests and
+ * replays can evaluate rules against a fixed time
+ */
+func WithClock(clock func() time.Time) Option {
+	return func(h *Parser) {
+		h.clock = clock
+	}
+}
+
+/*
+ * keep the state of rate() and count_distinct() in store, parsers that
+ * share a store share their counters
+ */
+func WithStateStore(store StateStore) Option {
+	return func(h *Parser) {
+		h.store = store
+	}
+}
+
+/*
+ * bind the $name parameters of the rule, values are strings or numbers;
+ * calling it again adds to the parameters
+ */
+func WithParams(params map[string]interface{}) Option {
+	return func(h *Parser) {
+		if h.paramValues == nil {
+			h.paramValues = make(map[string]interface{})
+		}
+		for k, v := range params {
+			h.paramValues[k] = v
+		}
+	}
+}
+
+/*
+ * read the files of import and include directives from fsys
+ */
+func WithResolver(fsys fs.FS) Option {
+	return func(h *Parser) {
+		h.resolver = fsys
+	}
+}
+
+/*
+ * syntax of the patterns of #, !#, #i and !#i: POSIX (default) or PERL
+ */
+func WithRegexSyntax(kind RKind_t) Option {
+	return func(h *Parser) {
+		h.regexSyntax = kind
+	}
+}
+
+/*
+ * how ==, !=, >=, <=, > and < (and so @ and !@) compare doubles, and
+ * integers with doubles:
+ *	ABSOLUTE	equal within tolerance
+ *	RELATIVE	equal within tolerance times the larger magnitude
+ *	EXACT		equal only when the same, tolerance is ignored
+ *	DECIMAL		equal when the same rounded to tolerance decimal
+ *			places (0 to 18)
+ * an invalid tolerance makes NewParser fail. without this option doubles
+ * compare as they always did: within 0.001 for ==, !=, >= and <=, >
+ * and < strict
+ */
+func WithNumericEquality(kind QKind_t, tolerance float64) Option {
+	return func(h *Parser) {
+		h.equality = &equality{kind: kind, tolerance: tolerance}
+	}
+}
+
+/*
+ * what happens when a string meets a number, in comparisons (==, @,
+ * any(), ...) and in builtin parameters:
+ *	IGNORE	they never compare equal, builtins reject the other kind
+ *		(default)
+ *	STRICT	an error, a compile error for two constants
+ *	NUMERIC	a string that reads as a number is compared as that
+ *		number, otherwise the number is compared as text
+ *	TEXT	the number is compared as its text
+ * with NUMERIC and TEXT builtins take numbers for strings and numeric
+ * strings for numbers
+ */
+func WithCoercion(kind CKind_t) Option {
+	return func(h *Parser) {
+		h.coercion = kind
+	}
+}
+
+/*
+ * normalise strings before they are compared (==, @, >, ...) and before
+ * regex terms (#, !#, #i, !#i) match them, constant patterns included:
+ * NFNONE (default), NFC or NFKC
+ */
+func WithNormalization(kind NKind_t) Option {
+	return func(h *Parser) {
+		h.normalization = kind
+	}
+}
+
+/*
+ * compare strings ignoring case, with the same folding in every locale
+ */
+func WithCaseFolding(fold bool) Option {
+	return func(h *Parser) {
+		h.caseFolding = fold
+	}
+}
+
+/*
+ * limit the number of evaluation steps (expressions, terms, functions,
+ * list items and function arguments) of a single Parse call
+ */
+func WithMaxSteps(n int) Option {
+	return func(h *Parser) {
+		h.maxSteps = n
+	}
+}
+
+/*
+ * limit the nesting of expressions and function calls during evaluation
+ */
+func WithMaxDepth(n int) Option {
+	return func(h *Parser) {
+		h.maxDepth = n
+	}
+}
+
+/*
+ * limit the size in bytes of input values and of strings built by the
+ * builtins
+ */
+func WithMaxValueSize(n int) Option {
+	return func(h *Parser) {
+		h.maxValueSize = n
+	}
+}
+
+/*
+ * reject rule text longer than n bytes
+ */
+func WithMaxSourceSize(n int) Option {
+	return func(h *Parser) {
+		h.limits.maxSourceSize = n
+	}
+}
+
+/*
+ * reject rules compiling to more than n syntax tree nodes
+ */
+func WithMaxNodes(n int) Option {
+	return func(h *Parser) {
+		h.limits.maxNodes = n
+	}
+}
+
+/*
+ * reject rules nesting parentheses (groups, lists and function calls)
+ * deeper than n
+ */
+func WithMaxNesting(n int) Option {
+	return func(h *Parser) {
+		h.limits.maxNesting = n
+	}
+}
+
+/*
+ * reject lists and function calls with more than n items
+ */
+func WithMaxListLen(n int) Option {
+	return func(h *Parser) {
+		h.limits.maxListLen = n
+	}
+}
+
+/*
+ * reject regex compiling to programs of more than n instructions
+ */
+func WithMaxRegexSize(n int) Option {
+	return func(h *Parser) {
+		h.limits.maxRegexSize = n
+	}
+}
@@ -0,0 +1,77 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+/*
+ * value of a host parameter: strings are strings, go integers of any
+ * width are integers (unsigned ones over math.MaxInt64 doubles) and go
+ * floats are doubles
+ */
+func paramFactor(name string, value interface{}) (*Factor, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return NewFactor(STRING, 0, v.String(), "", nil)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewIntegerFactor(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u > math.MaxInt64 {
+			return NewFactor(DOUBLE, float64(u), "", "", nil)
+		} else {
+			return NewIntegerFactor(int64(u))
+		}
+	case reflect.Float32, reflect.Float64:
+		return NewFactor(DOUBLE, v.Float(), "", "", nil)
+	}
+	return nil, errors.New(fmt.Sprintf("parameter '$%s' should be 'string', 'integer' or 'double', not %T", name, value))
+}
+
+/*
+ * add params to the parameters of h, replacing the ones of the same name
+ */
+func (h *Parser) bindParams(params map[string]interface{}) error {
+	bound := make(map[string]*Factor)
+	for k, v := range h.params {
+		bound[k] = v
+	}
+	for k, v := range params {
+		f, err := paramFactor(k, v)
+		if err != nil {
+			return err
+		}
+		bound[k] = f
+	}
+	h.params = bound
+	return nil
+}
+
+/*
+ * a parser sharing the compiled rule of h with some of its parameters
+ * bound to new values, the others keep their value
+ */
+func (h *Parser) Rebind(params map[string]interface{}) (*Parser, error) {
+	p := new(Parser)
+	*p = *h
+	if err := p.bindParams(params); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+/*
+ * names of the parameters used by the rule, without '$'
+ */
+func (h *Parser) Params() []string {
+	names := []string{}
+	for k := range h.used {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,183 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+ * the symbol input of Parse: Lookup returns the value of name, ok is
+ * false if there is none; Count is the number of symbols, as count()
+ * returns it. a provider may compute values on demand, Parse asks for
+ * each name at most once and keeps the answer until it returns
+ */
+type SymbolProvider interface {
+	Lookup(name string) (*Factor, bool, error)
+	Count() int
+}
+
+/*
+ * a symlist is a provider of its symbols, a nil symlist has none
+ */
+func (s *SymList) Lookup(name string) (*Factor, bool, error) {
+	for p := s; p != nil; p = p.Next {
+		if name == p.Name {
+			v, err := SymbolLookup(p, name)
+			return v, err == nil, err
+		}
+	}
+	return nil, false, nil
+}
+
+func (s *SymList) Count() int {
+	count := 0
+	seen := map[string]bool{}
+	for p := s; p != nil; p = p.Next {
+		// a query key given more than once is one symbol
+		if !seen[p.Name] {
+			seen[p.Name] = true
+			count += 1
+		}
+	}
+	return count
+}
+
+/*
+ * a provider computing each value when asked, such as a GeoIP lookup of
+ * 'geo.country'; it counts no symbols
+ */
+type ProviderFunc func(name string) (*Factor, bool, error)
+
+func (f ProviderFunc) Lookup(name string) (*Factor, bool, error) {
+	return f(name)
+}
+
+func (f ProviderFunc) Count() int {
+	return 0
+}
+
+/*
+ * providers asked in order, the first one knowing a name gives its value;
+ * an error of any of them ends the lookup. the count is the sum of their
+ * counts
+ */
+func ComposeProviders(providers ...SymbolProvider) SymbolProvider {
+	return composed(providers)
+}
+
+type composed []SymbolProvider
+
+func (c composed) Lookup(name string) (*Factor, bool, error) {
+	for _, p := range c {
+		if p == nil {
+			continue
+		}
+		v, ok, err := p.Lookup(name)
+		if err != nil || ok {
+			return v, ok, err
+		}
+	}
+	return nil, false, nil
+}
+
+func (c composed) Count() int {
+	count := 0
+	for _, p := range c {
+		if p != nil {
+			count += p.Count()
+		}
+	}
+	return count
+}
+
+/*
+ * answer of a provider kept for one Parse call
+ */
+type resolved struct {
+	value *Factor
+	ok    bool
+	err   error
+}
+
+/*
+ * value of an input symbol, providers other than a symlist are asked once
+ * per name and Parse call
+ */
+func (env *Env) lookupSymbol(name string) (*Factor, error) {
+	if env.symbols == nil {
+		return nil, errors.New(fmt.Sprintf("symbol '%s' not found", name))
+	}
+	if symlist, ok := env.symbols.(*SymList); ok {
+		return SymbolLookup(symlist, name)
+	}
+
+	r, ok := env.resolved[name]
+	if !ok {
+		r = new(resolved)
+		r.value, r.ok, r.err = env.symbols.Lookup(name)
+		if r.ok && r.value == nil && r.err == nil {
+			r.err = errors.New(fmt.Sprintf("symbol '%s' has no value", name))
+		}
+		if env.resolved == nil {
+			env.resolved = make(map[string]*resolved)
+		}
+		env.resolved[name] = r
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if !r.ok {
+		return nil, errors.New(fmt.Sprintf("symbol '%s' not found", name))
+	}
+	return r.value, nil
+}
+
+/*
+ * the values of an input symbol given more than once in a symlist (as
+ * tag=a&tag=b) as items, nil if it has a single value or the name is
+ * not that of an input symbol
+ */
+func (env *Env) lookupRepeated(name string) []*SymList {
+	if env.symbols == nil || strings.HasPrefix(name, "$") || env.inItem(name) {
+		return nil
+	}
+	if _, ok := env.bindings[name]; ok {
+		return nil
+	}
+	items, _ := repeatedItems(env.symbols, name)
+	if len(items) < 2 {
+		return nil
+	}
+	return items
+}
+
+/*
+ * the values of name in provider p; done is true once a provider has
+ * the name or may compute it. composed providers are searched in order
+ * up to the first provider that is not a symlist, asking it would break
+ * the promise of one lookup per name: repeated keys of a symlist are
+ * seen ahead of the providers computing values, not after them
+ */
+func repeatedItems(p SymbolProvider, name string) (items []*SymList, done bool) {
+	switch v := p.(type) {
+	case *SymList:
+		for s := v; s != nil; s = s.Next {
+			if name == s.Name {
+				items = append(items, &SymList{Kind: s.Kind, Value: s.Value})
+			}
+		}
+		return items, items != nil
+	case composed:
+		for _, p := range v {
+			if p == nil {
+				continue
+			}
+			if items, done := repeatedItems(p, name); done {
+				return items, true
+			}
+		}
+		return nil, false
+	}
+	return nil, true
+}
@@ -0,0 +1,85 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+ * items of an array value, a single value counts as an array of one item
+ * and a query key given several times as an array of its values
+ */
+func evalItems(name string, factor *Factor, env *Env) ([]*SymList, error) {
+	if v, ok := factor.Value.(string); ok && factor.Kind == VARIABLE {
+		if items := env.lookupRepeated(v); items != nil {
+			return items, nil
+		}
+	}
+	value, err := EvalFactor(factor, env)
+	if err != nil {
+		return nil, err
+	}
+	switch value.Kind {
+	case ARRAY:
+		if v, ok := value.Value.([]*SymList); ok {
+			return v, nil
+		}
+	case DOUBLE, INTEGER, STRING:
+		return []*SymList{{Kind: value.Kind, Value: value.Value}}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("%s() parameter should be 'array'", name))
+}
+
+/*
+ * any(items, expr), all(items, expr), none(items, expr): evaluate expr
+ * once per item with 'it' bound to the item ('it.name' for the fields of
+ * an object item); all() of no items is 1, any() of no items is 0
+ */
+func EvalQuant(kind TKind_t, items *Factor, expr *Expr, env *Env) (int, error) {
+	name := tkind2str(kind)
+	if items == nil || expr == nil {
+		return -1, errors.New(fmt.Sprintf("%s() with invalid parameter", name))
+	}
+
+	list, err := evalItems(name, items, env)
+	if err != nil {
+		return -1, err
+	}
+
+	outer := env.it
+	defer func() { env.it = outer }()
+	for _, item := range list {
+		if err := env.step(); err != nil {
+			return -1, err
+		}
+		env.it = &scope{item}
+		rc, err := EvalExpr(expr, env)
+		if err != nil {
+			return -1, err
+		}
+		switch {
+		case kind == ANY && rc == 1:
+			return 1, nil
+		case kind == ALL && rc != 1:
+			return 0, nil
+		case kind == NONE && rc == 1:
+			return 0, nil
+		}
+	}
+	return bool2int(kind != ANY), nil
+}
+
+/*
+ * size(items): number of items of an array, 1 for a single value
+ */
+func EvalSize(list *List, env *Env) (*Factor, error) {
+	if list == nil || list.Factor == nil || list.Next != nil {
+		return nil, errors.New("size() with invalid parameter")
+	}
+
+	items, err := evalItems("size", list.Factor, env)
+	if err != nil {
+		return nil, err
+	}
+	return NewIntegerFactor(int64(len(items)))
+}
@@ -0,0 +1,121 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ * split the parameters of a stateful builtin into its key (all leading
+ * parameters joined), the one before last and the window (last one)
+ */
+func evalStateArgs(name string, list *List, env *Env) (string, *Factor, time.Duration, error) {
+	var args []*Factor
+	for p := list; p != nil; p = p.Next {
+		args = append(args, p.Factor)
+	}
+	if len(args) < 3 {
+		return "", nil, 0, errors.New(fmt.Sprintf("%s() with invalid parameter", name))
+	}
+
+	var keys []string
+	for _, arg := range args[:len(args)-2] {
+		value, err := EvalFactor(arg, env)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		switch value.Kind {
+		case STRING:
+			keys = append(keys, value.Value.(string))
+		case DOUBLE:
+			keys = append(keys, strconv.FormatFloat(value.Value.(float64), 'f', -1, 64))
+		case INTEGER:
+			keys = append(keys, strconv.FormatInt(value.Value.(int64), 10))
+		default:
+			return "", nil, 0, errors.New(fmt.Sprintf("%s() key should be 'string' or 'double'", name))
+		}
+	}
+
+	arg, err := EvalFactor(args[len(args)-2], env)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	s, err := EvalConcat(name, &List{Factor: args[len(args)-1]}, env)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	window, err := time.ParseDuration(s)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	if window <= 0 {
+		return "", nil, 0, errors.New(fmt.Sprintf("%s() window should be positive", name))
+	}
+
+	key := name + "\x00" + s + "\x00" + strings.Join(keys, "\x00")
+	return key, arg, window, nil
+}
+
+/*
+ * rate(key..., limit, window): count a hit on key, 1 if there were more
+ * than limit hits within window, else 0
+ */
+func EvalRate(list *List, env *Env) (*Factor, error) {
+	key, arg, window, err := evalStateArgs("rate", list, env)
+	if err != nil {
+		return nil, err
+	}
+	if arg, err = env.asNumber("rate", arg); err != nil {
+		return nil, errors.New("rate() limit should be 'double'")
+	}
+	limit, err := cast2number(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	store := env.Store()
+	if store == nil {
+		return nil, errors.New("rate() needs a state store, see WithStateStore()")
+	}
+	hits, err := store.Hit(key, window, env.Now())
+	if err != nil {
+		return nil, err
+	}
+	return NewFactor(DOUBLE, float64(bool2int(hits > limit)), "", "", nil)
+}
+
+/*
+ * count_distinct(key..., member, window): record member under key,
+ * return the number of distinct members seen within window
+ */
+func EvalCountDistinct(list *List, env *Env) (*Factor, error) {
+	key, arg, window, err := evalStateArgs("count_distinct", list, env)
+	if err != nil {
+		return nil, err
+	}
+	var member string
+	switch arg.Kind {
+	case STRING:
+		member = arg.Value.(string)
+	case DOUBLE:
+		member = strconv.FormatFloat(arg.Value.(float64), 'f', -1, 64)
+	case INTEGER:
+		member = strconv.FormatInt(arg.Value.(int64), 10)
+	default:
+		return nil, errors.New("count_distinct() member should be 'string' or 'double'")
+	}
+
+	store := env.Store()
+	if store == nil {
+		return nil, errors.New("count_distinct() needs a state store, see WithStateStore()")
+	}
+	n, err := store.Distinct(key, member, window, env.Now())
+	if err != nil {
+		return nil, err
+	}
+	return NewFactor(DOUBLE, float64(n), "", "", nil)
+}
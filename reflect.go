@@ -0,0 +1,292 @@
+package filter
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+/*
+ * symlist of a struct or a pointer to one, with the symbol model of
+ * JsonToSymlist(): nested structs and maps are flattened with dotted
+ * names, slices and arrays become array symbols, pointers are followed
+ * and nil pointers, nil interfaces, bools and channels or funcs are left
+ * out. a field is named by its `gohap` tag, else its `json` tag, else its
+ * Go name; "-" drops it, ",omitempty" drops it when empty, embedded
+ * structs without a name have their fields promoted. signed integers are
+ * integers, unsigned ones too when they fit in int64, []byte is a string
+ * and values with a MarshalText method are the string it returns
+ */
+func SymlistFromStruct(v interface{}) (*SymList, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New(fmt.Sprintf("SymlistFromStruct() of %T, not a struct", v))
+	}
+	b := new(symlistBuilder)
+	if err := b.fields("", rv, 0); err != nil {
+		return nil, err
+	}
+	return b.head, nil
+}
+
+/*
+ * symlist of a map, values are handled as by SymlistFromStruct()
+ */
+func SymlistFromMap(m map[string]interface{}) (*SymList, error) {
+	b := new(symlistBuilder)
+	if err := b.fields("", reflect.ValueOf(m), 0); err != nil {
+		return nil, err
+	}
+	return b.head, nil
+}
+
+const maxReflectDepth = 32
+
+/*
+ * a symlist built in order, symbols are added at the tail
+ */
+type symlistBuilder struct {
+	head *SymList
+	tail *SymList
+}
+
+func (b *symlistBuilder) add(s *SymList) {
+	if b.head == nil {
+		b.head = s
+	} else {
+		b.tail.Next = s
+	}
+	b.tail = s
+}
+
+/*
+ * the symbols of the fields of a struct or the entries of a map
+ */
+func (b *symlistBuilder) fields(prefix string, v reflect.Value, depth int) error {
+	if depth > maxReflectDepth {
+		return errors.New(fmt.Sprintf("'%s' nested deeper than %d", strings.TrimSuffix(prefix, "."), maxReflectDepth))
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, f := range fieldPlan(v.Type()) {
+			field, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				continue // in a nil embedded pointer
+			}
+			if f.omitempty && field.IsZero() {
+				continue
+			}
+			if err := b.value(prefix+f.name, field, depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return errors.New(fmt.Sprintf("'%s' is a map of %s keys, not string", strings.TrimSuffix(prefix, "."), v.Type().Key()))
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := b.value(prefix+iter.Key().String(), iter.Value(), depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+ * the symbols of value v named name
+ */
+func (b *symlistBuilder) value(name string, v reflect.Value, depth int) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.CanInterface() {
+			if _, ok := v.Interface().(encoding.TextMarshaler); ok {
+				break
+			}
+		}
+		v = v.Elem()
+	}
+	if s, err := reflectScalar(v); err != nil || s != nil {
+		if s != nil {
+			s.Name = name
+			b.add(s)
+		}
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		// the fields of an object item are named as they are
+		if name != "" {
+			name += "."
+		}
+		return b.fields(name, v, depth)
+	case reflect.Slice, reflect.Array:
+		items, err := reflectItems(v, depth)
+		if err != nil {
+			return err
+		}
+		b.add(&SymList{Kind: ARRAY, Name: name, Value: items})
+	}
+	return nil
+}
+
+/*
+ * the items of a slice or array; values left out (nil pointers and
+ * interfaces, booleans, structs and maps without symbols) keep their
+ * place as empty arrays, as in the arrays of JsonToSymlist()
+ */
+func reflectItems(v reflect.Value, depth int) ([]*SymList, error) {
+	if depth > maxReflectDepth {
+		return nil, errors.New(fmt.Sprintf("array nested deeper than %d", maxReflectDepth))
+	}
+	items := []*SymList{}
+	for i := 0; i < v.Len(); i++ {
+		item := new(symlistBuilder)
+		if err := item.value("", v.Index(i), depth+1); err != nil {
+			return nil, err
+		}
+		if item.head == nil {
+			item.head, _ = NewSymlistArray("", []*SymList{})
+		}
+		items = append(items, item.head)
+	}
+	return items, nil
+}
+
+/*
+ * a symbol of a scalar value without name, nil if v is no scalar or is
+ * left out
+ */
+func reflectScalar(v reflect.Value) (*SymList, error) {
+	if v.CanInterface() {
+		switch u := v.Interface().(type) {
+		case json.Number:
+			kind, value, err := jsonNumber(string(u))
+			if err != nil {
+				return nil, err
+			}
+			return &SymList{Kind: kind, Value: value}, nil
+		case encoding.TextMarshaler:
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				return nil, nil
+			}
+			text, err := u.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return &SymList{Kind: STRING, Value: string(text)}, nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return &SymList{Kind: STRING, Value: v.String()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &SymList{Kind: INTEGER, Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u > math.MaxInt64 {
+			return &SymList{Kind: DOUBLE, Value: float64(u)}, nil
+		} else {
+			return &SymList{Kind: INTEGER, Value: int64(u)}, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return &SymList{Kind: DOUBLE, Value: v.Float()}, nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return &SymList{Kind: STRING, Value: string(v.Bytes())}, nil
+		}
+	}
+	return nil, nil
+}
+
+/*
+ * how to read the fields of a struct type, computed once per type
+ */
+type fieldInfo struct {
+	name      string
+	index     []int
+	omitempty bool
+}
+
+var fieldPlans sync.Map // reflect.Type -> []fieldInfo
+
+func fieldPlan(t reflect.Type) []fieldInfo {
+	if plan, ok := fieldPlans.Load(t); ok {
+		return plan.([]fieldInfo)
+	}
+	plan, _ := fieldPlans.LoadOrStore(t, buildFieldPlan(t, map[reflect.Type]bool{}))
+	return plan.([]fieldInfo)
+}
+
+/*
+ * the fields of t, fields of t itself hide promoted fields of the same
+ * name
+ */
+func buildFieldPlan(t reflect.Type, visiting map[reflect.Type]bool) []fieldInfo {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	plan := []fieldInfo{}
+	promoted := []fieldInfo{}
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("gohap")
+		if tag == "" {
+			tag = f.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+		omitempty := false
+		for _, opt := range strings.Split(opts, ",") {
+			omitempty = omitempty || opt == "omitempty"
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if visiting[ft] {
+				continue
+			}
+			for _, sub := range buildFieldPlan(ft, visiting) {
+				sub.index = append([]int{i}, sub.index...)
+				promoted = append(promoted, sub)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+		plan = append(plan, fieldInfo{name, []int{i}, omitempty})
+	}
+	for _, f := range promoted {
+		if !names[f.name] {
+			names[f.name] = true
+			plan = append(plan, f)
+		}
+	}
+	return plan
+}
@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sync"
+)
+
+type RKind_t int // for regex syntax
+
+const (
+	POSIX = RKind_t(0) // POSIX ERE, leftmost-longest (default)
+	PERL  = RKind_t(1) // RE2/Perl syntax with flags, leftmost-first
+)
+
+func rkind2str(kind RKind_t) string {
+	switch kind {
+	case POSIX:
+		return "posix"
+	case PERL:
+		return "perl"
+	}
+	return fmt.Sprintf("%d", int(kind))
+}
+
+/*
+ * compile the pattern of a #, !#, #i or !#i term; fold makes the match
+ * case-insensitive. POSIX patterns are checked against POSIX syntax
+ * first, as ERE has no flag syntax the folded pattern is then compiled
+ * as RE2 with leftmost-longest semantics kept
+ */
+func CompileRegex(pattern string, kind RKind_t, fold bool) (*regexp.Regexp, error) {
+	switch kind {
+	case POSIX:
+		if !fold {
+			return regexp.CompilePOSIX(pattern)
+		}
+		if _, err := syntax.Parse(pattern, syntax.POSIX); err != nil {
+			return nil, err
+		}
+		// (?m) keeps ^ and $ matching at line boundaries as in POSIX
+		regex, err := regexp.Compile("(?im:" + pattern + ")")
+		if err != nil {
+			return nil, err
+		}
+		regex.Longest()
+		return regex, nil
+	case PERL:
+		if fold {
+			pattern = "(?i)" + pattern
+		}
+		return regexp.Compile(pattern)
+	}
+	return nil, errors.New(fmt.Sprintf("regex syntax '%s' not supported", rkind2str(kind)))
+}
+
+func foldkind(kind TKind_t) bool {
+	return kind == MAI || kind == NMI
+}
+
+/*
+ * patterns taken from variables or function results are compiled when
+ * evaluated, the cache keeps them by pattern text; it is dropped as a
+ * whole once full so hostile input can't grow it without bound
+ */
+type regexCache struct {
+	mutex   sync.Mutex
+	entries map[string]*regexp.Regexp
+}
+
+const maxRegexCache = 1024
+
+func (c *regexCache) get(pattern string, kind RKind_t, fold bool, maxSize int) (*regexp.Regexp, error) {
+	key := "-:" + pattern
+	if fold {
+		key = "i:" + pattern
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if regex, ok := c.entries[key]; ok {
+		return regex, nil
+	}
+	regex, err := CompileRegex(pattern, kind, fold)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize > 0 {
+		if n, err := regexSize(regex.String(), syntax.Perl); err != nil {
+			return nil, err
+		} else if n > maxSize {
+			return nil, errors.New(fmt.Sprintf("regex '%s' larger than %d", pattern, maxSize))
+		}
+	}
+	if c.entries == nil || len(c.entries) >= maxRegexCache {
+		c.entries = make(map[string]*regexp.Regexp)
+	}
+	c.entries[key] = regex
+	return regex, nil
+}
@@ -2,7 +2,6 @@ package filter
 
 import (
 	"fmt"
-	"strconv"
 )
 import (
 	"bufio"
@@ -270,10 +269,10 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 		{[]bool{false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 61:
-					return -1
 				case 60:
 					return 1
+				case 61:
+					return -1
 				}
 				return -1
 			},
@@ -335,10 +334,10 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 			},
 			func(r rune) int {
 				switch r {
-				case 61:
-					return 2
 				case 33:
 					return -1
+				case 61:
+					return 2
 				}
 				return -1
 			},
@@ -402,6 +401,134 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 			},
 		}, []int{ /* Start-of-input transitions */ -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1}, nil},
 
+		// #i
+		{[]bool{false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 35:
+					return 1
+				case 105:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 35:
+					return -1
+				case 105:
+					return 2
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 35:
+					return -1
+				case 105:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1}, nil},
+
+		// !#i
+		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 33:
+					return 1
+				case 35:
+					return -1
+				case 105:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 33:
+					return -1
+				case 35:
+					return 2
+				case 105:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 33:
+					return -1
+				case 35:
+					return -1
+				case 105:
+					return 3
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 33:
+					return -1
+				case 35:
+					return -1
+				case 105:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
+
+		// ~
+		{[]bool{false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 126:
+					return 1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 126:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1}, []int{ /* End-of-input transitions */ -1, -1}, nil},
+
+		// !~
+		{[]bool{false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 33:
+					return 1
+				case 126:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 33:
+					return -1
+				case 126:
+					return 2
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 33:
+					return -1
+				case 126:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1}, nil},
+
 		// \(
 		{[]bool{false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
@@ -528,10 +655,10 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 			},
 			func(r rune) int {
 				switch r {
-				case 62:
-					return -1
 				case 61:
 					return -1
+				case 62:
+					return -1
 				}
 				return -1
 			},
@@ -560,6 +687,8 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 			},
 			func(r rune) int {
 				switch r {
+				case 100:
+					return -1
 				case 101:
 					return 2
 				case 102:
@@ -572,13 +701,15 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return -1
 				case 116:
 					return -1
-				case 100:
-					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
+				case 100:
+					return -1
+				case 101:
+					return -1
 				case 102:
 					return 3
 				case 97:
@@ -589,21 +720,11 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return -1
 				case 116:
 					return -1
-				case 100:
-					return -1
-				case 101:
-					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 117:
-					return -1
-				case 108:
-					return -1
-				case 116:
-					return -1
 				case 100:
 					return -1
 				case 101:
@@ -612,6 +733,12 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return -1
 				case 97:
 					return 4
+				case 117:
+					return -1
+				case 108:
+					return -1
+				case 116:
+					return -1
 				}
 				return -1
 			},
@@ -655,6 +782,8 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 			},
 			func(r rune) int {
 				switch r {
+				case 100:
+					return -1
 				case 101:
 					return -1
 				case 102:
@@ -667,13 +796,15 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return -1
 				case 116:
 					return 7
-				case 100:
-					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
+				case 100:
+					return -1
+				case 101:
+					return -1
 				case 102:
 					return -1
 				case 97:
@@ -684,16 +815,12 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return -1
 				case 116:
 					return -1
-				case 100:
-					return -1
-				case 101:
-					return -1
 				}
 				return -1
 			},
 		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1}, nil},
 
-		// len
+		// let
 		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
@@ -701,7 +828,7 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return 1
 				case 101:
 					return -1
-				case 110:
+				case 116:
 					return -1
 				}
 				return -1
@@ -712,7 +839,7 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return -1
 				case 101:
 					return 2
-				case 110:
+				case 116:
 					return -1
 				}
 				return -1
@@ -723,7 +850,7 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return -1
 				case 101:
 					return -1
-				case 110:
+				case 116:
 					return 3
 				}
 				return -1
@@ -734,102 +861,108 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 					return -1
 				case 101:
 					return -1
-				case 110:
+				case 116:
 					return -1
 				}
 				return -1
 			},
 		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
 
-		// md5
+		// def
 		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 109:
-					return 1
 				case 100:
+					return 1
+				case 101:
 					return -1
-				case 53:
+				case 102:
 					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 109:
-					return -1
 				case 100:
+					return -1
+				case 101:
 					return 2
-				case 53:
+				case 102:
 					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 109:
-					return -1
 				case 100:
 					return -1
-				case 53:
+				case 101:
+					return -1
+				case 102:
 					return 3
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 109:
-					return -1
 				case 100:
 					return -1
-				case 53:
+				case 101:
+					return -1
+				case 102:
 					return -1
 				}
 				return -1
 			},
 		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
 
-		// count
-		{[]bool{false, false, false, false, false, true}, []func(rune) int{ // Transitions
+		// import
+		{[]bool{false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 110:
+				case 105:
+					return 1
+				case 109:
 					return -1
-				case 116:
+				case 112:
 					return -1
-				case 99:
-					return 1
 				case 111:
 					return -1
-				case 117:
+				case 114:
+					return -1
+				case 116:
 					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 111:
+				case 105:
+					return -1
+				case 109:
 					return 2
-				case 117:
+				case 112:
 					return -1
-				case 110:
+				case 111:
 					return -1
-				case 116:
+				case 114:
 					return -1
-				case 99:
+				case 116:
 					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 99:
+				case 105:
 					return -1
-				case 111:
+				case 109:
 					return -1
-				case 117:
+				case 112:
 					return 3
-				case 110:
+				case 111:
+					return -1
+				case 114:
 					return -1
 				case 116:
 					return -1
@@ -838,144 +971,167 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 			},
 			func(r rune) int {
 				switch r {
-				case 117:
+				case 105:
 					return -1
-				case 110:
-					return 4
-				case 116:
+				case 109:
 					return -1
-				case 99:
+				case 112:
 					return -1
 				case 111:
+					return 4
+				case 114:
+					return -1
+				case 116:
 					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 110:
+				case 105:
 					return -1
-				case 116:
-					return 5
-				case 99:
+				case 109:
+					return -1
+				case 112:
 					return -1
 				case 111:
 					return -1
-				case 117:
+				case 114:
+					return 5
+				case 116:
 					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 111:
+				case 105:
 					return -1
-				case 117:
+				case 109:
 					return -1
-				case 110:
+				case 112:
 					return -1
-				case 116:
+				case 111:
 					return -1
-				case 99:
+				case 114:
 					return -1
+				case 116:
+					return 6
 				}
 				return -1
 			},
-		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1}, nil},
-
-		// atoi
-		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 97:
-					return 1
-				case 116:
+				case 105:
 					return -1
-				case 111:
+				case 109:
 					return -1
-				case 105:
+				case 112:
 					return -1
-				}
-				return -1
-			},
-			func(r rune) int {
-				switch r {
 				case 111:
 					return -1
-				case 105:
-					return -1
-				case 97:
+				case 114:
 					return -1
 				case 116:
-					return 2
+					return -1
 				}
 				return -1
 			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// include
+		{[]bool{false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 97:
+				case 105:
+					return 1
+				case 110:
 					return -1
-				case 116:
+				case 99:
 					return -1
-				case 111:
-					return 3
-				case 105:
+				case 108:
+					return -1
+				case 117:
+					return -1
+				case 100:
+					return -1
+				case 101:
 					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 97:
+				case 105:
 					return -1
-				case 116:
+				case 110:
+					return 2
+				case 99:
 					return -1
-				case 111:
+				case 108:
+					return -1
+				case 117:
+					return -1
+				case 100:
+					return -1
+				case 101:
 					return -1
-				case 105:
-					return 4
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 116:
+				case 105:
 					return -1
-				case 111:
+				case 110:
 					return -1
-				case 105:
+				case 99:
+					return 3
+				case 108:
 					return -1
-				case 97:
+				case 117:
+					return -1
+				case 100:
+					return -1
+				case 101:
 					return -1
 				}
 				return -1
 			},
-		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
-
-		// itoa
-		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 97:
-					return -1
 				case 105:
-					return 1
-				case 116:
 					return -1
-				case 111:
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 108:
+					return 4
+				case 117:
+					return -1
+				case 100:
+					return -1
+				case 101:
 					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 116:
-					return 2
-				case 111:
+				case 105:
 					return -1
-				case 97:
+				case 110:
 					return -1
-				case 105:
+				case 99:
+					return -1
+				case 108:
+					return -1
+				case 117:
+					return 5
+				case 100:
+					return -1
+				case 101:
 					return -1
 				}
 				return -1
@@ -984,11 +1140,17 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 				switch r {
 				case 105:
 					return -1
-				case 116:
+				case 110:
 					return -1
-				case 111:
-					return 3
-				case 97:
+				case 99:
+					return -1
+				case 108:
+					return -1
+				case 117:
+					return -1
+				case 100:
+					return 6
+				case 101:
 					return -1
 				}
 				return -1
@@ -997,162 +1159,6238 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 				switch r {
 				case 105:
 					return -1
-				case 116:
+				case 110:
 					return -1
-				case 111:
+				case 99:
 					return -1
-				case 97:
-					return 4
+				case 108:
+					return -1
+				case 117:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return 7
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 111:
+				case 105:
 					return -1
-				case 97:
+				case 110:
 					return -1
-				case 105:
+				case 99:
 					return -1
-				case 116:
+				case 108:
+					return -1
+				case 117:
+					return -1
+				case 100:
+					return -1
+				case 101:
 					return -1
 				}
 				return -1
 			},
-		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1}, nil},
 
-		// '[^']*'
-		{[]bool{false, false, true, false}, []func(rune) int{ // Transitions
+		// as
+		{[]bool{false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 39:
+				case 97:
 					return 1
+				case 115:
+					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 39:
+				case 97:
+					return -1
+				case 115:
 					return 2
 				}
-				return 3
+				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 39:
+				case 97:
+					return -1
+				case 115:
 					return -1
 				}
 				return -1
 			},
-			func(r rune) int {
-				switch r {
-				case 39:
-					return 2
-				}
-				return 3
-			},
-		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1}, nil},
 
-		// [_a-zA-Z][_a-zA-Z0-9]*
-		{[]bool{false, true, true}, []func(rune) int{ // Transitions
+		// =
+		{[]bool{false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 95:
-					return 1
-				}
-				switch {
-				case 48 <= r && r <= 57:
-					return -1
-				case 65 <= r && r <= 90:
-					return 1
-				case 97 <= r && r <= 122:
+				case 61:
 					return 1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 95:
-					return 2
-				}
-				switch {
-				case 48 <= r && r <= 57:
-					return 2
-				case 65 <= r && r <= 90:
-					return 2
-				case 97 <= r && r <= 122:
-					return 2
+				case 61:
+					return -1
 				}
 				return -1
 			},
+		}, []int{ /* Start-of-input transitions */ -1, -1}, []int{ /* End-of-input transitions */ -1, -1}, nil},
+
+		// len
+		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 95:
-					return 2
-				}
-				switch {
-				case 48 <= r && r <= 57:
-					return 2
-				case 65 <= r && r <= 90:
-					return 2
-				case 97 <= r && r <= 122:
-					return 2
+				case 108:
+					return 1
+				case 101:
+					return -1
+				case 110:
+					return -1
 				}
 				return -1
 			},
-		}, []int{ /* Start-of-input transitions */ -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1}, nil},
-
-		// -?[0-9]+(\.[0-9]*)*
-		{[]bool{false, false, true, true, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 46:
+				case 108:
 					return -1
-				case 45:
-					return 1
-				}
-				switch {
-				case 48 <= r && r <= 57:
+				case 101:
 					return 2
+				case 110:
+					return -1
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 45:
+				case 108:
 					return -1
-				case 46:
+				case 101:
 					return -1
-				}
-				switch {
-				case 48 <= r && r <= 57:
-					return 2
+				case 110:
+					return 3
 				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 45:
+				case 108:
+					return -1
+				case 101:
+					return -1
+				case 110:
 					return -1
-				case 46:
-					return 3
-				}
-				switch {
-				case 48 <= r && r <= 57:
-					return 2
 				}
 				return -1
 			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
+
+		// md5
+		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
-				case 45:
+				case 109:
+					return 1
+				case 100:
+					return -1
+				case 53:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 109:
+					return -1
+				case 100:
+					return 2
+				case 53:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 109:
+					return -1
+				case 100:
+					return -1
+				case 53:
+					return 3
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 109:
+					return -1
+				case 100:
+					return -1
+				case 53:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
+
+		// count
+		{[]bool{false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 99:
+					return 1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return 2
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return 3
+				case 110:
+					return -1
+				case 116:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return 4
+				case 116:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return 5
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1}, nil},
+
+		// atoi
+		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 97:
+					return 1
+				case 116:
+					return -1
+				case 111:
+					return -1
+				case 105:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 116:
+					return 2
+				case 111:
+					return -1
+				case 105:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 111:
+					return 3
+				case 105:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 111:
+					return -1
+				case 105:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 111:
+					return -1
+				case 105:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+
+		// itoa
+		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 105:
+					return 1
+				case 116:
+					return -1
+				case 111:
+					return -1
+				case 97:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 105:
+					return -1
+				case 116:
+					return 2
+				case 111:
+					return -1
+				case 97:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 105:
+					return -1
+				case 116:
+					return -1
+				case 111:
+					return 3
+				case 97:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 105:
+					return -1
+				case 116:
+					return -1
+				case 111:
+					return -1
+				case 97:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 105:
+					return -1
+				case 116:
+					return -1
+				case 111:
+					return -1
+				case 97:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+
+		// sha1
+		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 115:
+					return 1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 49:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return 2
+				case 97:
+					return -1
+				case 49:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return 3
+				case 49:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 49:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 49:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+
+		// sha256
+		{[]bool{false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 115:
+					return 1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return 2
+				case 97:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return 3
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 50:
+					return 4
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return 5
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return 6
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// sha512
+		{[]bool{false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 115:
+					return 1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 53:
+					return -1
+				case 49:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return 2
+				case 97:
+					return -1
+				case 53:
+					return -1
+				case 49:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return 3
+				case 53:
+					return -1
+				case 49:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 53:
+					return 4
+				case 49:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 53:
+					return -1
+				case 49:
+					return 5
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 53:
+					return -1
+				case 49:
+					return -1
+				case 50:
+					return 6
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 104:
+					return -1
+				case 97:
+					return -1
+				case 53:
+					return -1
+				case 49:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// hmac_sha256
+		{[]bool{false, false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 104:
+					return 1
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return 2
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return 3
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return 4
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return 5
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return 6
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return 7
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return 8
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return 9
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return 10
+				case 54:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return 11
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 99:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 50:
+					return -1
+				case 53:
+					return -1
+				case 54:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// crc32
+		{[]bool{false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 99:
+					return 1
+				case 114:
+					return -1
+				case 51:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 114:
+					return 2
+				case 51:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return 3
+				case 114:
+					return -1
+				case 51:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 114:
+					return -1
+				case 51:
+					return 4
+				case 50:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 114:
+					return -1
+				case 51:
+					return -1
+				case 50:
+					return 5
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 114:
+					return -1
+				case 51:
+					return -1
+				case 50:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1}, nil},
+
+		// base64_encode
+		{[]bool{false, false, false, false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 98:
+					return 1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return 2
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return 3
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return 4
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return 5
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return 6
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return 7
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return 8
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return 9
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return 10
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return 11
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return 12
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return 13
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// base64_decode
+		{[]bool{false, false, false, false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 98:
+					return 1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return 2
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return 3
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return 4
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return 5
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return 6
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return 7
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return 8
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return 9
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return 10
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return 11
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return 12
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return 13
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 97:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 54:
+					return -1
+				case 52:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// hex_encode
+		{[]bool{false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 104:
+					return 1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return 2
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return 3
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return 4
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return 5
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return 6
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return 7
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return 8
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return 9
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return 10
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// hex_decode
+		{[]bool{false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 104:
+					return 1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return 2
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return 3
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return 4
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return 5
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return 6
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return 7
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return 8
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return 9
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return 10
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 101:
+					return -1
+				case 120:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// url_encode
+		{[]bool{false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 117:
+					return 1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return 2
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return 3
+				case 95:
+					return -1
+				case 101:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return 4
+				case 101:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return 5
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return -1
+				case 110:
+					return 6
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return 7
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return 8
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return 9
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return 10
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 101:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// url_decode
+		{[]bool{false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 117:
+					return 1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return 2
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return 3
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return 4
+				case 100:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return 5
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return 6
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return 7
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return 8
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return 9
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return 10
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 108:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 111:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// secure_eq
+		{[]bool{false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 115:
+					return 1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 95:
+					return -1
+				case 113:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return 2
+				case 99:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 95:
+					return -1
+				case 113:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return 3
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 95:
+					return -1
+				case 113:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 117:
+					return 4
+				case 114:
+					return -1
+				case 95:
+					return -1
+				case 113:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return 5
+				case 95:
+					return -1
+				case 113:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return 6
+				case 99:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 95:
+					return -1
+				case 113:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 95:
+					return 7
+				case 113:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return 8
+				case 99:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 95:
+					return -1
+				case 113:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 95:
+					return -1
+				case 113:
+					return 9
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 99:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 95:
+					return -1
+				case 113:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// now
+		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 110:
+					return 1
+				case 111:
+					return -1
+				case 119:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 110:
+					return -1
+				case 111:
+					return 2
+				case 119:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 110:
+					return -1
+				case 111:
+					return -1
+				case 119:
+					return 3
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 110:
+					return -1
+				case 111:
+					return -1
+				case 119:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
+
+		// unix
+		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 117:
+					return 1
+				case 110:
+					return -1
+				case 105:
+					return -1
+				case 120:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return 2
+				case 105:
+					return -1
+				case 120:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 105:
+					return 3
+				case 120:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 105:
+					return -1
+				case 120:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 105:
+					return -1
+				case 120:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+
+		// parse_time
+		{[]bool{false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 112:
+					return 1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return 2
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return 3
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return 4
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return 5
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return 6
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 116:
+					return 7
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return 8
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return 9
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return 10
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 112:
+					return -1
+				case 97:
+					return -1
+				case 114:
+					return -1
+				case 115:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// hour
+		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 104:
+					return 1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 111:
+					return 2
+				case 117:
+					return -1
+				case 114:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return 3
+				case 114:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 104:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+
+		// weekday
+		{[]bool{false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 119:
+					return 1
+				case 101:
+					return -1
+				case 107:
+					return -1
+				case 100:
+					return -1
+				case 97:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 119:
+					return -1
+				case 101:
+					return 2
+				case 107:
+					return -1
+				case 100:
+					return -1
+				case 97:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 119:
+					return -1
+				case 101:
+					return 3
+				case 107:
+					return -1
+				case 100:
+					return -1
+				case 97:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 119:
+					return -1
+				case 101:
+					return -1
+				case 107:
+					return 4
+				case 100:
+					return -1
+				case 97:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 119:
+					return -1
+				case 101:
+					return -1
+				case 107:
+					return -1
+				case 100:
+					return 5
+				case 97:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 119:
+					return -1
+				case 101:
+					return -1
+				case 107:
+					return -1
+				case 100:
+					return -1
+				case 97:
+					return 6
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 119:
+					return -1
+				case 101:
+					return -1
+				case 107:
+					return -1
+				case 100:
+					return -1
+				case 97:
+					return -1
+				case 121:
+					return 7
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 119:
+					return -1
+				case 101:
+					return -1
+				case 107:
+					return -1
+				case 100:
+					return -1
+				case 97:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// duration
+		{[]bool{false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 100:
+					return 1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 100:
+					return -1
+				case 117:
+					return 2
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 100:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return 3
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 100:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 97:
+					return 4
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 100:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return 5
+				case 105:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 100:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return 6
+				case 111:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 100:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 111:
+					return 7
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 100:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return 8
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 100:
+					return -1
+				case 117:
+					return -1
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// since
+		{[]bool{false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 115:
+					return 1
+				case 105:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return 2
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return -1
+				case 110:
+					return 3
+				case 99:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return 4
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 101:
+					return 5
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return -1
+				case 110:
+					return -1
+				case 99:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1}, nil},
+
+		// until
+		{[]bool{false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 117:
+					return 1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 108:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return 2
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 108:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return 3
+				case 105:
+					return -1
+				case 108:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return 4
+				case 108:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 108:
+					return 5
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 108:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1}, nil},
+
+		// time_add
+		{[]bool{false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 116:
+					return 1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 97:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return 2
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 97:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return 3
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 97:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return 4
+				case 95:
+					return -1
+				case 97:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return 5
+				case 97:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 97:
+					return 6
+				case 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 97:
+					return -1
+				case 100:
+					return 7
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 97:
+					return -1
+				case 100:
+					return 8
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 97:
+					return -1
+				case 100:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// time_sub
+		{[]bool{false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 116:
+					return 1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 117:
+					return -1
+				case 98:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return 2
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 117:
+					return -1
+				case 98:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return 3
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 117:
+					return -1
+				case 98:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return 4
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 117:
+					return -1
+				case 98:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return 5
+				case 115:
+					return -1
+				case 117:
+					return -1
+				case 98:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return 6
+				case 117:
+					return -1
+				case 98:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 117:
+					return 7
+				case 98:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 117:
+					return -1
+				case 98:
+					return 8
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 116:
+					return -1
+				case 105:
+					return -1
+				case 109:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 115:
+					return -1
+				case 117:
+					return -1
+				case 98:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// rate
+		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 114:
+					return 1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 114:
+					return -1
+				case 97:
+					return 2
+				case 116:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return 3
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 114:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+
+		// count_distinct
+		{[]bool{false, false, false, false, false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 99:
+					return 1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return 2
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return 3
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return 4
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return 5
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return 6
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return 7
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return 8
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return 9
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return 10
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return 11
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return 12
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return 13
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return 14
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 117:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 95:
+					return -1
+				case 100:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// match
+		{[]bool{false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 109:
+					return 1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 99:
+					return -1
+				case 104:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 109:
+					return -1
+				case 97:
+					return 2
+				case 116:
+					return -1
+				case 99:
+					return -1
+				case 104:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return 3
+				case 99:
+					return -1
+				case 104:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 99:
+					return 4
+				case 104:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 99:
+					return -1
+				case 104:
+					return 5
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 109:
+					return -1
+				case 97:
+					return -1
+				case 116:
+					return -1
+				case 99:
+					return -1
+				case 104:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1}, nil},
+
+		// contains_any
+		{[]bool{false, false, false, false, false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 99:
+					return 1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return 2
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return 3
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return 4
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return 5
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return 6
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return 7
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return 8
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return 9
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return 10
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return 11
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return 12
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 99:
+					return -1
+				case 111:
+					return -1
+				case 110:
+					return -1
+				case 116:
+					return -1
+				case 97:
+					return -1
+				case 105:
+					return -1
+				case 115:
+					return -1
+				case 95:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// size
+		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 115:
+					return 1
+				case 105:
+					return -1
+				case 122:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return 2
+				case 122:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return -1
+				case 122:
+					return 3
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return -1
+				case 122:
+					return -1
+				case 101:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 115:
+					return -1
+				case 105:
+					return -1
+				case 122:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+
+		// byte_len
+		{[]bool{false, false, false, false, false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 98:
+					return 1
+				case 121:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 108:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 121:
+					return 2
+				case 116:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 108:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 121:
+					return -1
+				case 116:
+					return 3
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 108:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 121:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return 4
+				case 95:
+					return -1
+				case 108:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 121:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return 5
+				case 108:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 121:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 108:
+					return 6
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 121:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return 7
+				case 95:
+					return -1
+				case 108:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 121:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 108:
+					return -1
+				case 110:
+					return 8
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 98:
+					return -1
+				case 121:
+					return -1
+				case 116:
+					return -1
+				case 101:
+					return -1
+				case 95:
+					return -1
+				case 108:
+					return -1
+				case 110:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// any
+		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 97:
+					return 1
+				case 110:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 110:
+					return 2
+				case 121:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 110:
+					return -1
+				case 121:
+					return 3
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 110:
+					return -1
+				case 121:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
+
+		// all
+		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 97:
+					return 1
+				case 108:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 108:
+					return 2
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 108:
+					return 3
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 97:
+					return -1
+				case 108:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
+
+		// none
+		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 110:
+					return 1
+				case 111:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 110:
+					return -1
+				case 111:
+					return 2
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 110:
+					return 3
+				case 111:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 110:
+					return -1
+				case 111:
+					return -1
+				case 101:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 110:
+					return -1
+				case 111:
+					return -1
+				case 101:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+
+		// \$[_a-zA-Z][_a-zA-Z0-9]*
+		{[]bool{false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 36:
+					return 1
+				case 95:
+					return -1
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return -1
+				case 97 <= r && r <= 122:
+					return -1
+				case 48 <= r && r <= 57:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 36:
+					return -1
+				case 95:
+					return 2
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return 2
+				case 97 <= r && r <= 122:
+					return 2
+				case 48 <= r && r <= 57:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 36:
+					return -1
+				case 95:
+					return 2
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return 2
+				case 97 <= r && r <= 122:
+					return 2
+				case 48 <= r && r <= 57:
+					return 2
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1}, nil},
+
+		// '[^']*'
+		{[]bool{false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 39:
+					return 1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 39:
+					return 2
+				}
+				return 1
+			},
+			func(r rune) int {
+				switch r {
+				case 39:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1}, nil},
+
+		// [_a-zA-Z][_a-zA-Z0-9]*(\.[_a-zA-Z0-9]+)*(@[_a-zA-Z][_a-zA-Z0-9]*)?
+		{[]bool{false, true, false, false, true, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 95:
+					return 1
+				case 46:
+					return -1
+				case 64:
+					return -1
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return 1
+				case 97 <= r && r <= 122:
+					return 1
+				case 48 <= r && r <= 57:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 95:
+					return 1
+				case 46:
+					return 2
+				case 64:
+					return 3
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return 1
+				case 97 <= r && r <= 122:
+					return 1
+				case 48 <= r && r <= 57:
+					return 1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 95:
+					return 4
+				case 46:
+					return -1
+				case 64:
+					return -1
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return 4
+				case 97 <= r && r <= 122:
+					return 4
+				case 48 <= r && r <= 57:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 95:
+					return 5
+				case 46:
+					return -1
+				case 64:
+					return -1
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return 5
+				case 97 <= r && r <= 122:
+					return 5
+				case 48 <= r && r <= 57:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 95:
+					return 4
+				case 46:
+					return 2
+				case 64:
+					return 3
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return 4
+				case 97 <= r && r <= 122:
+					return 4
+				case 48 <= r && r <= 57:
+					return 4
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 95:
+					return 5
+				case 46:
+					return -1
+				case 64:
+					return -1
+				}
+				switch {
+				case 65 <= r && r <= 90:
+					return 5
+				case 97 <= r && r <= 122:
+					return 5
+				case 48 <= r && r <= 57:
+					return 5
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1}, nil},
+
+		// -?(0[xX][0-9a-fA-F]+|[0-9]+\.?[0-9]*([eE][-+]?[0-9]+)?|\.[0-9]+([eE][-+]?[0-9]+)?)
+		{[]bool{false, false, true, true, false, false, false, true, true, true, false, true, false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 45:
+					return 1
+				case 48:
+					return 2
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return 4
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 3
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 2
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return 4
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 3
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 3
+				case 120:
+					return 5
+				case 88:
+					return 5
+				case 69:
+					return 6
+				case 70:
+					return -1
+				case 101:
+					return 6
+				case 102:
+					return -1
+				case 46:
+					return 7
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 3
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 3
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return 6
+				case 70:
+					return -1
+				case 101:
+					return 6
+				case 102:
+					return -1
+				case 46:
+					return 7
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 3
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 8
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 8
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 9
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return 9
+				case 70:
+					return 9
+				case 101:
+					return 9
+				case 102:
+					return 9
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 9
+				case 65 <= r && r <= 68:
+					return 9
+				case 97 <= r && r <= 100:
+					return 9
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return 10
+				case 48:
+					return 11
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return 10
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 11
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 7
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return 6
+				case 70:
+					return -1
+				case 101:
+					return 6
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 7
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 8
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return 12
+				case 70:
+					return -1
+				case 101:
+					return 12
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 8
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 9
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return 9
+				case 70:
+					return 9
+				case 101:
+					return 9
+				case 102:
+					return 9
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 9
+				case 65 <= r && r <= 68:
+					return 9
+				case 97 <= r && r <= 100:
+					return 9
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 11
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 11
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 11
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 11
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return 13
+				case 48:
+					return 14
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return 13
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 14
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 14
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 14
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 48:
+					return 14
+				case 120:
+					return -1
+				case 88:
+					return -1
+				case 69:
+					return -1
+				case 70:
+					return -1
+				case 101:
+					return -1
+				case 102:
+					return -1
+				case 46:
+					return -1
+				case 43:
+					return -1
+				}
+				switch {
+				case 49 <= r && r <= 57:
+					return 14
+				case 65 <= r && r <= 68:
+					return -1
+				case 97 <= r && r <= 100:
+					return -1
+				}
+				return -1
+			},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, nil},
+
+		// -?[0-9.][_a-zA-Z0-9.]*
+		{[]bool{false, false, true}, []func(rune) int{ // Transitions
+			func(r rune) int {
+				switch r {
+				case 45:
+					return 1
+				case 46:
+					return 2
+				case 95:
+					return -1
+				}
+				switch {
+				case 48 <= r && r <= 57:
+					return 2
+				case 65 <= r && r <= 90:
+					return -1
+				case 97 <= r && r <= 122:
+					return -1
+				}
+				return -1
+			},
+			func(r rune) int {
+				switch r {
+				case 45:
+					return -1
+				case 46:
+					return 2
+				case 95:
 					return -1
-				case 46:
-					return 3
 				}
 				switch {
 				case 48 <= r && r <= 57:
-					return 4
+					return 2
+				case 65 <= r && r <= 90:
+					return -1
+				case 97 <= r && r <= 122:
+					return -1
 				}
 				return -1
 			},
@@ -1161,18 +7399,24 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 				case 45:
 					return -1
 				case 46:
-					return 3
+					return 2
+				case 95:
+					return 2
 				}
 				switch {
 				case 48 <= r && r <= 57:
-					return 4
+					return 2
+				case 65 <= r && r <= 90:
+					return 2
+				case 97 <= r && r <= 122:
+					return 2
 				}
 				return -1
 			},
-		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1}, nil},
 
 		// \/\/.*\n
-		{[]bool{false, false, false, false, true}, []func(rune) int{ // Transitions
+		{[]bool{false, false, false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
 				switch r {
 				case 47:
@@ -1194,31 +7438,22 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 			func(r rune) int {
 				switch r {
 				case 47:
-					return 3
+					return 2
 				case 10:
-					return 4
-				}
-				return 3
-			},
-			func(r rune) int {
-				switch r {
-				case 47:
 					return 3
-				case 10:
-					return 4
 				}
-				return 3
+				return 2
 			},
 			func(r rune) int {
 				switch r {
 				case 47:
-					return 3
+					return -1
 				case 10:
-					return 4
+					return -1
 				}
-				return 3
+				return -1
 			},
-		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1, -1}, nil},
+		}, []int{ /* Start-of-input transitions */ -1, -1, -1, -1}, []int{ /* End-of-input transitions */ -1, -1, -1, -1}, nil},
 
 		// [ \t\n;]
 		{[]bool{false, true}, []func(rune) int{ // Transitions
@@ -1226,24 +7461,24 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 				switch r {
 				case 32:
 					return 1
-				case 9:
-					return 1
-				case 10:
-					return 1
 				case 59:
 					return 1
 				}
+				switch {
+				case 9 <= r && r <= 10:
+					return 1
+				}
 				return -1
 			},
 			func(r rune) int {
 				switch r {
-				case 10:
+				case 32:
 					return -1
 				case 59:
 					return -1
-				case 32:
-					return -1
-				case 9:
+				}
+				switch {
+				case 9 <= r && r <= 10:
 					return -1
 				}
 				return -1
@@ -1253,9 +7488,17 @@ func NewLexerWithInit(in io.Reader, initFun func(*Lexer)) *Lexer {
 		// .
 		{[]bool{false, true}, []func(rune) int{ // Transitions
 			func(r rune) int {
+				switch r {
+				case 10:
+					return -1
+				}
 				return 1
 			},
 			func(r rune) int {
+				switch r {
+				case 10:
+					return -1
+				}
 				return -1
 			},
 		}, []int{ /* Start-of-input transitions */ -1, -1}, []int{ /* End-of-input transitions */ -1, -1}, nil},
@@ -1352,100 +7595,353 @@ func (yylex *Lexer) Lex(lval *yySymType) int {
 			continue
 		case 10:
 			{
-				return LPAREN
+				lval.fn = int(MAI)
+				return CMP
 			}
 			continue
 		case 11:
 			{
-				return RPAREN
+				lval.fn = int(NMI)
+				return CMP
 			}
 			continue
 		case 12:
 			{
-				return COMMA
+				lval.fn = int(GL)
+				return CMP
 			}
 			continue
 		case 13:
 			{
-				return LAND
+				lval.fn = int(NG)
+				return CMP
 			}
 			continue
 		case 14:
 			{
-				return LOR
+				return LPAREN
 			}
 			continue
 		case 15:
 			{
-				return GET
+				return RPAREN
 			}
 			continue
 		case 16:
 			{
-				return DEFAULT
+				return COMMA
 			}
 			continue
 		case 17:
+			{
+				return LAND
+			}
+			continue
+		case 18:
+			{
+				return LOR
+			}
+			continue
+		case 19:
+			{
+				return GET
+			}
+			continue
+		case 20:
+			{
+				return DEFAULT
+			}
+			continue
+		case 21:
+			{
+				return LET
+			}
+			continue
+		case 22:
+			{
+				return DEF
+			}
+			continue
+		case 23:
+			{
+				return IMPORT
+			}
+			continue
+		case 24:
+			{
+				return INCLUDE
+			}
+			continue
+		case 25:
+			{
+				return AS
+			}
+			continue
+		case 26:
+			{
+				return ASSIGN
+			}
+			continue
+		case 27:
 			{
 				lval.fn = int(LEN)
 				return FUNC
 			}
 			continue
-		case 18:
+		case 28:
 			{
 				lval.fn = int(MD5)
 				return FUNC
 			}
 			continue
-		case 19:
+		case 29:
 			{
 				lval.fn = int(COUNT)
 				return FUNC
 			}
 			continue
-		case 20:
+		case 30:
 			{
 				lval.fn = int(ATOI)
 				return FUNC
 			}
 			continue
-		case 21:
+		case 31:
 			{
 				lval.fn = int(ITOA)
 				return FUNC
 			}
 			continue
-		case 22:
+		case 32:
+			{
+				lval.fn = int(SHA1)
+				return FUNC
+			}
+			continue
+		case 33:
+			{
+				lval.fn = int(SHA256)
+				return FUNC
+			}
+			continue
+		case 34:
+			{
+				lval.fn = int(SHA512)
+				return FUNC
+			}
+			continue
+		case 35:
+			{
+				lval.fn = int(HMAC_SHA256)
+				return FUNC
+			}
+			continue
+		case 36:
+			{
+				lval.fn = int(CRC32)
+				return FUNC
+			}
+			continue
+		case 37:
+			{
+				lval.fn = int(BASE64_ENCODE)
+				return FUNC
+			}
+			continue
+		case 38:
+			{
+				lval.fn = int(BASE64_DECODE)
+				return FUNC
+			}
+			continue
+		case 39:
+			{
+				lval.fn = int(HEX_ENCODE)
+				return FUNC
+			}
+			continue
+		case 40:
+			{
+				lval.fn = int(HEX_DECODE)
+				return FUNC
+			}
+			continue
+		case 41:
+			{
+				lval.fn = int(URL_ENCODE)
+				return FUNC
+			}
+			continue
+		case 42:
+			{
+				lval.fn = int(URL_DECODE)
+				return FUNC
+			}
+			continue
+		case 43:
+			{
+				lval.fn = int(SECURE_EQ)
+				return FUNC
+			}
+			continue
+		case 44:
+			{
+				lval.fn = int(NOW)
+				return FUNC
+			}
+			continue
+		case 45:
+			{
+				lval.fn = int(UNIX)
+				return FUNC
+			}
+			continue
+		case 46:
+			{
+				lval.fn = int(PARSE_TIME)
+				return FUNC
+			}
+			continue
+		case 47:
+			{
+				lval.fn = int(HOUR)
+				return FUNC
+			}
+			continue
+		case 48:
+			{
+				lval.fn = int(WEEKDAY)
+				return FUNC
+			}
+			continue
+		case 49:
+			{
+				lval.fn = int(DURATION)
+				return FUNC
+			}
+			continue
+		case 50:
+			{
+				lval.fn = int(SINCE)
+				return FUNC
+			}
+			continue
+		case 51:
+			{
+				lval.fn = int(UNTIL)
+				return FUNC
+			}
+			continue
+		case 52:
+			{
+				lval.fn = int(TIME_ADD)
+				return FUNC
+			}
+			continue
+		case 53:
+			{
+				lval.fn = int(TIME_SUB)
+				return FUNC
+			}
+			continue
+		case 54:
+			{
+				lval.fn = int(RATE)
+				return FUNC
+			}
+			continue
+		case 55:
+			{
+				lval.fn = int(COUNT_DISTINCT)
+				return FUNC
+			}
+			continue
+		case 56:
+			{
+				lval.fn = int(MATCH)
+				return FUNC
+			}
+			continue
+		case 57:
+			{
+				lval.fn = int(CONTAINS_ANY)
+				return FUNC
+			}
+			continue
+		case 58:
+			{
+				lval.fn = int(SIZE)
+				return FUNC
+			}
+			continue
+		case 59:
+			{
+				lval.fn = int(BYTE_LEN)
+				return FUNC
+			}
+			continue
+		case 60:
+			{
+				lval.fn = int(ANY)
+				return QUANT
+			}
+			continue
+		case 61:
+			{
+				lval.fn = int(ALL)
+				return QUANT
+			}
+			continue
+		case 62:
+			{
+				lval.fn = int(NONE)
+				return QUANT
+			}
+			continue
+		case 63:
+			{
+				lval.str = yylex.Text()[1:]
+				return PARAM
+			}
+			continue
+		case 64:
 			{
 				lval.str = yylex.Text()
 				lval.str = lval.str[1 : len(lval.str)-1]
 				return STR
 			}
 			continue
-		case 23:
+		case 65:
 			{
 				lval.str = yylex.Text()
 				return VAR
 			}
 			continue
-		case 24:
+		case 66:
 			{
-				f, _ := strconv.ParseFloat(yylex.Text(), 64)
-				lval.dval = f
-				return NUM
+				return lexNumber(yylex.Text(), lval)
 			}
 			continue
-		case 25:
+		case 67:
 			{
+				lval.str = yylex.Text()
+				return BADNUM
 			}
 			continue
-		case 26:
+		case 68:
 			{
+				yylex.advance()
 			}
 			continue
-		case 27:
+		case 69:
+			{
+				yylex.advance()
+			}
+			continue
+		case 70:
 			{
 				fmt.Println("Lexer: invalid charactor", yylex.Text())
+				yylex.advance()
 			}
 			continue
 		}
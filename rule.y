@@ -1,8 +1,6 @@
 %{
 package filter
-import ("fmt";"io";"errors";"sync");
-var g_grammer *Grammer;
-var g_mutex  sync.Mutex
+import ("bytes";"context";"fmt";"io";"io/fs";"io/ioutil";"errors";"time");
 %}
 
 %union {
@@ -14,24 +12,36 @@ var g_mutex  sync.Mutex
 	fun *Func
 	str string
 	dval float64
+	ival int64
 	fn int
 }
 
-%token COMMA LPAREN RPAREN LAND LOR GET DEFAULT 
+%token COMMA LPAREN RPAREN LAND LOR GET DEFAULT LET DEF ASSIGN IMPORT INCLUDE AS 
 %type <grammer> grammer
 %type <expr> expr
 %type <term> term
 %type <factor> factor
 %type <fun> fun
 %type <list> list
-%token <str> VAR STR
+%token <str> VAR STR PARAM
 %token <dval> NUM
-%token <fn> CMP CONTAIN FUNC
+%token <ival> INT
+%token <str> BADNUM
+%type <dval> ret
+%token <fn> CMP CONTAIN FUNC QUANT
 
 %%
-start: grammer { g_mutex.Lock(); g_grammer = $1; g_mutex.Unlock(); };
-grammer: expr GET NUM grammer {var err error; if $$, err = NewGrammer(EGET, $1, $3, $4); err != nil {panic(err); }}
-| DEFAULT GET NUM grammer {var err error; if $$, err = NewGrammer(DGET, nil, $3, $4); err != nil { panic(err); }} 
+start: decls grammer { yylex.(*ruleLexer).grammer = $2; };
+decls: decls decl
+|              { }
+decl: LET VAR ASSIGN factor { yylex.(*ruleLexer).bind($2, $4) }
+| DEF VAR ASSIGN expr { yylex.(*ruleLexer).bind($2, $4) }
+| IMPORT STR { yylex.(*ruleLexer).load($2, "", false) }
+| IMPORT STR AS VAR { yylex.(*ruleLexer).load($2, $4, false) }
+| INCLUDE STR { yylex.(*ruleLexer).load($2, "", true) }
+
+grammer: expr GET ret grammer {var err error; if $$, err = NewGrammer(EGET, $1, $3, $4); err != nil {panic(err); }}
+| DEFAULT GET ret grammer {var err error; if $$, err = NewGrammer(DGET, nil, $3, $4); err != nil { panic(err); }} 
 | expr grammer {var err error; if $$, err = NewGrammer(EEXPR, $1, 0, $2); err != nil { panic(err); }}
 |              {$$ = nil; }
 
@@ -39,20 +49,30 @@ expr: expr LAND term {var err error; if $$, err = NewExpr(AND, $1, $3); err != n
 | expr LOR term {var err error; if $$, err = NewExpr(OR, $1, $3); err != nil { panic(err); }}
 | term {var err error; if $$, err = NewExpr(TERM, nil, $1); err != nil { panic(err); }}
 
-term: factor CONTAIN LPAREN list RPAREN {var err error; if $$, err = NewTerm(TKind_t($2), $1, $4, nil, nil);  err != nil {panic(err);} }
-| factor CMP factor  {var err error; if $$, err = NewTerm(TKind_t($2), $1, nil, $3, nil); err != nil {panic(err); }}
+term: factor CONTAIN LPAREN list RPAREN {var err error; yylex.(*ruleLexer).checkList($4); if $$, err = NewTerm(TKind_t($2), $1, $4, nil, nil);  err != nil {panic(err);}; yylex.(*ruleLexer).checkTerm($$)}
+| factor CMP factor  {var err error; if $$, err = NewTerm(TKind_t($2), $1, nil, $3, nil); err != nil {panic(err); }; yylex.(*ruleLexer).checkTerm($$)}
 |  LPAREN expr RPAREN {var err error; if $$, err = NewTerm(EXPR, nil, nil, nil, $2); err != nil { panic(err); }}
+| QUANT LPAREN factor COMMA expr RPAREN {var err error; if $$, err = NewTerm(TKind_t($1), $3, nil, nil, $5); err != nil { panic(err); }}
+| fun {var err error; var f *Factor; if f, err = NewFactor(FUNCTION, 0, "", "", $1); err != nil { panic(err); }; if $$, err = NewTerm(BOOL, f, nil, nil, nil); err != nil { panic(err); }}
+| VAR {var err error; var f *Factor; yylex.(*ruleLexer).checkRef($1); if f, err = NewFactor(VARIABLE, 0, "", $1, nil); err != nil { panic(err); }; if $$, err = NewTerm(BOOL, f, nil, nil, nil); err != nil { panic(err); }}
 
 factor : VAR {var err error; if $$, err = NewFactor(VARIABLE, 0, "", $1, nil); err != nil { panic(err); }; }
 | STR {var err error; if $$, err = NewFactor(STRING, 0, $1, "", nil); err != nil { panic(err); };}
 | NUM {var err error; if $$, err = NewFactor(DOUBLE, $1, "", "", nil); err != nil { panic(err); };}
+| INT {var err error; if $$, err = NewIntegerFactor($1); err != nil { panic(err); };}
+| PARAM {var err error; yylex.(*ruleLexer).checkParam($1); if $$, err = NewFactor(VARIABLE, 0, "", "$" + $1, nil); err != nil { panic(err); };}
 | fun {var err error; if $$, err = NewFactor(FUNCTION, 0, "", "", $1); err !=nil { panic(err); };}
 
+ret : NUM { $$ = $1 }
+| INT { $$ = float64($1) }
+
 list : factor {var err error; if $$, err = NewList($1, nil); err != nil { panic(err); };}
 | factor COMMA list {var err error; if $$, err = NewList($1, $3); err != nil { panic(err);};}
 
-fun: FUNC LPAREN list RPAREN  {var err error; if $$, err = NewFunc(FnKind_t($1), $3); err != nil { panic(err); }}
+fun: FUNC LPAREN list RPAREN  {var err error; if $$, err = NewFunc(FnKind_t($1), $3); err != nil { panic(err); }; yylex.(*ruleLexer).checkFunc($$)}
 | FUNC LPAREN RPAREN {var err error; if $$, err = NewFunc(FnKind_t($1),nil); err != nil { panic(err); }} 
+| FUNC LPAREN factor COMMA LPAREN list RPAREN RPAREN {var err error; var f *Factor; yylex.(*ruleLexer).checkList($6); if f, err = NewListFactor($6); err != nil { panic(err); }; if $$, err = NewFunc(FnKind_t($1), &List{$3, &List{f, nil}}); err != nil { panic(err); }; yylex.(*ruleLexer).checkFunc($$)}
+| FUNC LPAREN factor COMMA LPAREN list RPAREN COMMA list RPAREN {var err error; var f *Factor; yylex.(*ruleLexer).checkList($6); if f, err = NewListFactor($6); err != nil { panic(err); }; if $$, err = NewFunc(FnKind_t($1), &List{$3, &List{f, $9}}); err != nil { panic(err); }; yylex.(*ruleLexer).checkFunc($$)}
 
 %%
 
@@ -61,40 +81,111 @@ fun: FUNC LPAREN list RPAREN  {var err error; if $$, err = NewFunc(FnKind_t($1),
  */
 type Parser struct {
     grammer *Grammer	
+    bindings map[string]*Binding
+    resolver fs.FS
+
+    // host parameters as given to WithParams() and as bound, and the
+    // names the rule uses
+    paramValues map[string]interface{}
+    params      map[string]*Factor
+    used        map[string]bool
+    clock   func() time.Time
+    store   StateStore
+
+    // evaluation budgets, 0 means unlimited
+    maxSteps     int
+    maxDepth     int
+    maxValueSize int
+
+    limits limits
+
+    // syntax of regex terms and the cache of their non constant patterns
+    regexSyntax RKind_t
+    regexes     *regexCache
+
+    // comparison of doubles, nil for the default, and of strings with
+    // numbers
+    equality *equality
+    coercion CKind_t
+
+    // unicode handling of string comparisons and regex
+    normalization NKind_t
+    caseFolding   bool
 }
 
 /*
    analyze input rule script and generate parser handle
+   errors about the rule text are returned as *CompileError
  */
-func NewParser(in io.Reader) (h *Parser, err error) {
+func NewParser(in io.Reader, opts ...Option) (h *Parser, err error) {
+    h = new(Parser)
+    h.regexes = new(regexCache)
+    for _, opt := range opts {
+        opt(h)
+    }
+    if err = h.bindParams(h.paramValues); err != nil {
+        return nil, err
+    }
+    if q := h.equality; q != nil {
+        if h.equality, err = newEquality(q.kind, q.tolerance); err != nil {
+            return nil, err
+        }
+    }
+    if max := h.limits.maxSourceSize; max > 0 {
+        src, err := ioutil.ReadAll(io.LimitReader(in, int64(max)+1))
+        if err != nil {
+            return nil, err
+        }
+        if len(src) > max {
+            return nil, &CompileError{"", 0, 0, fmt.Sprintf("rule source larger than %d bytes", max)}
+        }
+        in = bytes.NewReader(src)
+    }
+    lex := newRuleLexer(h, NewLexer(in))
 	defer func() {
 		if e := recover(); e != nil {
-			h, err = nil, errors.New(fmt.Sprint(e)) 
+			h, err = nil, lex.errorf(e)
 		}
 	}()
-    h = new(Parser)
-    lex := NewLexer(in)
     yyParse(lex)
-	g_mutex.Lock()
-	h.grammer = g_grammer
-	g_mutex.Unlock()
+	h.grammer = lex.grammer
+	h.bindings = lex.bindings
+	h.used = lex.params
 	if h.grammer == nil {
 		return h, errors.New("invalid rule");
 	}	
+    if err = lex.checkRule(); err != nil {
+        return nil, err
+    }
     return h, err; 
 }
 
 /*
    get parse result
-   symlist is created by calling QueryToSymlist() or JsonToSymlist() API
+   symbols is a symlist created by calling QueryToSymlist() or JsonToSymlist() API,
+   or any SymbolProvider
+ */
+func (h *Parser)Parse(symbols SymbolProvider) (ret int, err error) {
+	return h.ParseContext(context.Background(), symbols)
+}
+
+/*
+   get parse result, giving up with ctx.Err() once ctx is done
+   budgets set by WithMaxSteps(), WithMaxDepth() and WithMaxValueSize()
+   are enforced here too, running over one returns a *BudgetExceeded
  */
-func (h *Parser)Parse(symlist *SymList) (ret int, err error) {
+func (h *Parser)ParseContext(ctx context.Context, symbols SymbolProvider) (ret int, err error) {
 	defer func() {
 		if e := recover(); e != nil {
 			ret, err = -1, errors.New(fmt.Sprint(e)) 
 		}
 	}()
-	ret, err = EvalGrammer(h.grammer, symlist);
+	if err = ctx.Err(); err != nil {
+		return -1, err
+	}
+	env := NewEnv(h, symbols)
+	env.ctx = ctx
+	ret, err = EvalGrammer(h.grammer, env);
 	return
 }
 
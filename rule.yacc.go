@@ -1,3 +1,5 @@
+// Code generated by goyacc -o=rule.yacc.go rule.y. DO NOT EDIT.
+
 //line rule.y:2
 package filter
 
@@ -5,16 +7,17 @@ import __yyfmt__ "fmt"
 
 //line rule.y:2
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"sync"
+	"io/fs"
+	"io/ioutil"
+	"time"
 )
 
-var g_grammer *Grammer
-var g_mutex sync.Mutex
-
-//line rule.y:8
+//line rule.y:6
 type yySymType struct {
 	yys     int
 	grammer *Grammer
@@ -25,6 +28,7 @@ type yySymType struct {
 	fun     *Func
 	str     string
 	dval    float64
+	ival    int64
 	fn      int
 }
 
@@ -35,14 +39,27 @@ const LAND = 57349
 const LOR = 57350
 const GET = 57351
 const DEFAULT = 57352
-const VAR = 57353
-const STR = 57354
-const NUM = 57355
-const CMP = 57356
-const CONTAIN = 57357
-const FUNC = 57358
-
-var yyToknames = []string{
+const LET = 57353
+const DEF = 57354
+const ASSIGN = 57355
+const IMPORT = 57356
+const INCLUDE = 57357
+const AS = 57358
+const VAR = 57359
+const STR = 57360
+const PARAM = 57361
+const NUM = 57362
+const INT = 57363
+const BADNUM = 57364
+const CMP = 57365
+const CONTAIN = 57366
+const FUNC = 57367
+const QUANT = 57368
+
+var yyToknames = [...]string{
+	"$end",
+	"error",
+	"$unk",
 	"COMMA",
 	"LPAREN",
 	"RPAREN",
@@ -50,154 +67,293 @@ var yyToknames = []string{
 	"LOR",
 	"GET",
 	"DEFAULT",
+	"LET",
+	"DEF",
+	"ASSIGN",
+	"IMPORT",
+	"INCLUDE",
+	"AS",
 	"VAR",
 	"STR",
+	"PARAM",
 	"NUM",
+	"INT",
+	"BADNUM",
 	"CMP",
 	"CONTAIN",
 	"FUNC",
+	"QUANT",
 }
-var yyStatenames = []string{}
+
+var yyStatenames = [...]string{}
 
 const yyEofCode = 1
 const yyErrCode = 2
-const yyMaxDepth = 200
+const yyInitialStackSize = 16
 
-//line rule.y:57
+//line rule.y:77
 
 /*
-   parser handle
+parser handle
 */
 type Parser struct {
-	grammer *Grammer
+	grammer  *Grammer
+	bindings map[string]*Binding
+	resolver fs.FS
+
+	// host parameters as given to WithParams() and as bound, and the
+	// names the rule uses
+	paramValues map[string]interface{}
+	params      map[string]*Factor
+	used        map[string]bool
+	clock       func() time.Time
+	store       StateStore
+
+	// evaluation budgets, 0 means unlimited
+	maxSteps     int
+	maxDepth     int
+	maxValueSize int
+
+	limits limits
+
+	// syntax of regex terms and the cache of their non constant patterns
+	regexSyntax RKind_t
+	regexes     *regexCache
+
+	// comparison of doubles, nil for the default, and of strings with
+	// numbers
+	equality *equality
+	coercion CKind_t
+
+	// unicode handling of string comparisons and regex
+	normalization NKind_t
+	caseFolding   bool
 }
 
 /*
-   analyze input rule script and generate parser handle
+analyze input rule script and generate parser handle
+errors about the rule text are returned as *CompileError
 */
-func NewParser(in io.Reader) (h *Parser, err error) {
+func NewParser(in io.Reader, opts ...Option) (h *Parser, err error) {
+	h = new(Parser)
+	h.regexes = new(regexCache)
+	for _, opt := range opts {
+		opt(h)
+	}
+	if err = h.bindParams(h.paramValues); err != nil {
+		return nil, err
+	}
+	if q := h.equality; q != nil {
+		if h.equality, err = newEquality(q.kind, q.tolerance); err != nil {
+			return nil, err
+		}
+	}
+	if max := h.limits.maxSourceSize; max > 0 {
+		src, err := ioutil.ReadAll(io.LimitReader(in, int64(max)+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(src) > max {
+			return nil, &CompileError{"", 0, 0, fmt.Sprintf("rule source larger than %d bytes", max)}
+		}
+		in = bytes.NewReader(src)
+	}
+	lex := newRuleLexer(h, NewLexer(in))
 	defer func() {
 		if e := recover(); e != nil {
-			h, err = nil, errors.New(fmt.Sprint(e))
+			h, err = nil, lex.errorf(e)
 		}
 	}()
-	h = new(Parser)
-	lex := NewLexer(in)
 	yyParse(lex)
-	g_mutex.Lock()
-	h.grammer = g_grammer
-	g_mutex.Unlock()
+	h.grammer = lex.grammer
+	h.bindings = lex.bindings
+	h.used = lex.params
 	if h.grammer == nil {
 		return h, errors.New("invalid rule")
 	}
+	if err = lex.checkRule(); err != nil {
+		return nil, err
+	}
 	return h, err
 }
 
 /*
-   get parse result
-   symlist is created by calling QueryToSymlist() or JsonToSymlist() API
+get parse result
+symbols is a symlist created by calling QueryToSymlist() or JsonToSymlist() API,
+or any SymbolProvider
+*/
+func (h *Parser) Parse(symbols SymbolProvider) (ret int, err error) {
+	return h.ParseContext(context.Background(), symbols)
+}
+
+/*
+get parse result, giving up with ctx.Err() once ctx is done
+budgets set by WithMaxSteps(), WithMaxDepth() and WithMaxValueSize()
+are enforced here too, running over one returns a *BudgetExceeded
 */
-func (h *Parser) Parse(symlist *SymList) (ret int, err error) {
+func (h *Parser) ParseContext(ctx context.Context, symbols SymbolProvider) (ret int, err error) {
 	defer func() {
 		if e := recover(); e != nil {
 			ret, err = -1, errors.New(fmt.Sprint(e))
 		}
 	}()
-	ret, err = EvalGrammer(h.grammer, symlist)
+	if err = ctx.Err(); err != nil {
+		return -1, err
+	}
+	env := NewEnv(h, symbols)
+	env.ctx = ctx
+	ret, err = EvalGrammer(h.grammer, env)
 	return
 }
 
 //line yacctab:1
-var yyExca = []int{
+var yyExca = [...]int8{
 	-1, 1,
 	1, -1,
 	-2, 0,
+	-1, 15,
+	23, 27,
+	24, 27,
+	-2, 20,
+	-1, 16,
+	23, 22,
+	24, 22,
+	-2, 21,
 }
 
-const yyNprod = 20
 const yyPrivate = 57344
 
-var yyTokenNames []string
-var yyStates []string
-
-const yyLast = 61
-
-var yyAct = []int{
-
-	6, 29, 5, 2, 19, 18, 7, 14, 15, 16,
-	13, 4, 8, 9, 10, 25, 22, 12, 23, 24,
-	27, 17, 31, 37, 35, 7, 32, 31, 34, 33,
-	4, 8, 9, 10, 30, 3, 12, 31, 38, 8,
-	9, 10, 7, 20, 12, 28, 15, 16, 8, 9,
-	10, 26, 21, 12, 8, 9, 10, 36, 1, 12,
-	11,
+const yyLast = 150
+
+var yyAct = [...]int8{
+	15, 5, 67, 36, 12, 32, 31, 3, 37, 38,
+	30, 13, 29, 23, 58, 33, 6, 7, 8, 28,
+	9, 10, 27, 16, 17, 20, 18, 19, 44, 43,
+	41, 21, 14, 48, 11, 48, 48, 46, 51, 50,
+	53, 13, 42, 48, 54, 57, 48, 56, 59, 55,
+	60, 24, 25, 16, 17, 20, 18, 19, 26, 39,
+	40, 21, 14, 66, 48, 73, 48, 72, 60, 48,
+	60, 70, 75, 60, 48, 71, 74, 13, 60, 24,
+	25, 22, 6, 69, 24, 25, 49, 24, 25, 16,
+	17, 20, 18, 19, 13, 65, 64, 21, 14, 6,
+	62, 45, 35, 34, 68, 63, 16, 17, 20, 18,
+	19, 61, 4, 2, 21, 14, 47, 17, 20, 18,
+	19, 52, 1, 0, 21, 0, 0, 0, 0, 0,
+	0, 0, 47, 17, 20, 18, 19, 0, 0, 0,
+	21, 47, 17, 20, 18, 19, 0, 0, 0, 21,
 }
-var yyPact = []int{
 
-	20, -1000, -1000, 1, 12, -1000, -10, 37, -1000, -1000,
-	-1000, -1000, 47, 3, -1000, 37, 37, 2, 46, 43,
-	39, 28, 20, -1000, -1000, 20, 43, -1000, -1000, 18,
-	-1000, 53, -1000, -1000, 17, -1000, 43, -1000, -1000,
+var yyPact = [...]int16{
+	-1000, -1000, 6, -1000, -1000, 72, 49, 5, 2, -6,
+	-8, -1000, -18, 36, 98, -1000, -1000, -1000, -1000, -1000,
+	-1000, 97, -12, -1000, 36, 36, -12, 29, 16, 12,
+	-1000, 96, 124, 80, 124, 115, 89, -1000, -1000, -1000,
+	-1000, 89, 124, 36, -3, 124, -1000, -1000, -1000, -1000,
+	107, 94, -1000, 101, -1000, -1000, -1000, 44, -1000, 90,
+	91, 36, -1000, 99, -1000, 124, 77, -1000, 124, -1000,
+	69, 61, -1000, 124, 66, -1000,
 }
-var yyPgo = []int{
 
-	0, 3, 35, 2, 0, 60, 1, 58,
+var yyPgo = [...]int8{
+	0, 7, 1, 34, 4, 0, 2, 3, 122, 113,
+	112,
 }
-var yyR1 = []int{
 
-	0, 7, 1, 1, 1, 1, 2, 2, 2, 3,
-	3, 3, 4, 4, 4, 4, 6, 6, 5, 5,
+var yyR1 = [...]int8{
+	0, 8, 9, 9, 10, 10, 10, 10, 10, 1,
+	1, 1, 1, 2, 2, 2, 3, 3, 3, 3,
+	3, 3, 4, 4, 4, 4, 4, 4, 7, 7,
+	6, 6, 5, 5, 5, 5,
 }
-var yyR2 = []int{
 
-	0, 1, 4, 4, 2, 0, 3, 3, 1, 5,
-	3, 3, 1, 1, 1, 1, 1, 3, 4, 3,
+var yyR2 = [...]int8{
+	0, 2, 2, 0, 4, 4, 2, 4, 2, 4,
+	4, 2, 0, 3, 3, 1, 5, 3, 3, 6,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 3, 4, 3, 8, 10,
 }
-var yyChk = []int{
 
-	-1000, -7, -1, -2, 10, -3, -4, 5, 11, 12,
-	13, -5, 16, 9, -1, 7, 8, 9, 15, 14,
-	-2, 5, 13, -3, -3, 13, 5, -4, 6, -6,
-	6, -4, -1, -1, -6, 6, 4, 6, -6,
+var yyChk = [...]int16{
+	-1000, -8, -9, -1, -10, -2, 10, 11, 12, 14,
+	15, -3, -4, 5, 26, -5, 17, 18, 20, 21,
+	19, 25, 9, -1, 7, 8, 9, 17, 17, 18,
+	18, 24, 23, -2, 5, 5, -7, 20, 21, -3,
+	-3, -7, 13, 13, 16, 5, -4, 17, -5, 6,
+	-4, -6, 6, -4, -1, -1, -4, -2, 17, -6,
+	-4, 4, 6, 4, 6, 4, -2, -6, 5, 6,
+	-6, 6, 6, 4, -6, 6,
 }
-var yyDef = []int{
 
-	5, -2, 1, 5, 0, 8, 0, 0, 12, 13,
-	14, 15, 0, 0, 4, 0, 0, 0, 0, 0,
-	0, 0, 5, 6, 7, 5, 0, 10, 11, 0,
-	19, 16, 2, 3, 0, 18, 0, 9, 17,
+var yyDef = [...]int8{
+	3, -2, 12, 1, 2, 12, 0, 0, 0, 0,
+	0, 15, 0, 0, 0, -2, -2, 23, 24, 25,
+	26, 0, 0, 11, 0, 0, 0, 0, 0, 6,
+	8, 0, 0, 0, 0, 0, 12, 28, 29, 13,
+	14, 12, 0, 0, 0, 0, 17, 22, 27, 18,
+	0, 0, 33, 30, 9, 10, 4, 5, 7, 0,
+	30, 0, 32, 0, 16, 0, 0, 31, 0, 19,
+	0, 0, 34, 0, 0, 35,
 }
-var yyTok1 = []int{
 
+var yyTok1 = [...]int8{
 	1,
 }
-var yyTok2 = []int{
 
+var yyTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
-	12, 13, 14, 15, 16,
+	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
+	22, 23, 24, 25, 26,
 }
-var yyTok3 = []int{
+
+var yyTok3 = [...]int8{
 	0,
 }
 
+var yyErrorMessages = [...]struct {
+	state int
+	token int
+	msg   string
+}{}
+
 //line yaccpar:1
 
 /*	parser for yacc output	*/
 
-var yyDebug = 0
+var (
+	yyDebug        = 0
+	yyErrorVerbose = false
+)
 
 type yyLexer interface {
 	Lex(lval *yySymType) int
 	Error(s string)
 }
 
+type yyParser interface {
+	Parse(yyLexer) int
+	Lookahead() int
+}
+
+type yyParserImpl struct {
+	lval  yySymType
+	stack [yyInitialStackSize]yySymType
+	char  int
+}
+
+func (p *yyParserImpl) Lookahead() int {
+	return p.char
+}
+
+func yyNewParser() yyParser {
+	return &yyParserImpl{}
+}
+
 const yyFlag = -1000
 
 func yyTokname(c int) string {
-	// 4 is TOKSTART above
-	if c >= 4 && c-4 < len(yyToknames) {
-		if yyToknames[c-4] != "" {
-			return yyToknames[c-4]
+	if c >= 1 && c-1 < len(yyToknames) {
+		if yyToknames[c-1] != "" {
+			return yyToknames[c-1]
 		}
 	}
 	return __yyfmt__.Sprintf("tok-%v", c)
@@ -212,51 +368,127 @@ func yyStatname(s int) string {
 	return __yyfmt__.Sprintf("state-%v", s)
 }
 
-func yylex1(lex yyLexer, lval *yySymType) int {
-	c := 0
-	char := lex.Lex(lval)
+func yyErrorMessage(state, lookAhead int) string {
+	const TOKSTART = 4
+
+	if !yyErrorVerbose {
+		return "syntax error"
+	}
+
+	for _, e := range yyErrorMessages {
+		if e.state == state && e.token == lookAhead {
+			return "syntax error: " + e.msg
+		}
+	}
+
+	res := "syntax error: unexpected " + yyTokname(lookAhead)
+
+	// To match Bison, suggest at most four expected tokens.
+	expected := make([]int, 0, 4)
+
+	// Look for shiftable tokens.
+	base := int(yyPact[state])
+	for tok := TOKSTART; tok-1 < len(yyToknames); tok++ {
+		if n := base + tok; n >= 0 && n < yyLast && int(yyChk[int(yyAct[n])]) == tok {
+			if len(expected) == cap(expected) {
+				return res
+			}
+			expected = append(expected, tok)
+		}
+	}
+
+	if yyDef[state] == -2 {
+		i := 0
+		for yyExca[i] != -1 || int(yyExca[i+1]) != state {
+			i += 2
+		}
+
+		// Look for tokens that we accept or reduce.
+		for i += 2; yyExca[i] >= 0; i += 2 {
+			tok := int(yyExca[i])
+			if tok < TOKSTART || yyExca[i+1] == 0 {
+				continue
+			}
+			if len(expected) == cap(expected) {
+				return res
+			}
+			expected = append(expected, tok)
+		}
+
+		// If the default action is to accept or reduce, give up.
+		if yyExca[i+1] != 0 {
+			return res
+		}
+	}
+
+	for i, tok := range expected {
+		if i == 0 {
+			res += ", expecting "
+		} else {
+			res += " or "
+		}
+		res += yyTokname(tok)
+	}
+	return res
+}
+
+func yylex1(lex yyLexer, lval *yySymType) (char, token int) {
+	token = 0
+	char = lex.Lex(lval)
 	if char <= 0 {
-		c = yyTok1[0]
+		token = int(yyTok1[0])
 		goto out
 	}
 	if char < len(yyTok1) {
-		c = yyTok1[char]
+		token = int(yyTok1[char])
 		goto out
 	}
 	if char >= yyPrivate {
 		if char < yyPrivate+len(yyTok2) {
-			c = yyTok2[char-yyPrivate]
+			token = int(yyTok2[char-yyPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(yyTok3); i += 2 {
-		c = yyTok3[i+0]
-		if c == char {
-			c = yyTok3[i+1]
+		token = int(yyTok3[i+0])
+		if token == char {
+			token = int(yyTok3[i+1])
 			goto out
 		}
 	}
 
 out:
-	if c == 0 {
-		c = yyTok2[1] /* unknown char */
+	if token == 0 {
+		token = int(yyTok2[1]) /* unknown char */
 	}
 	if yyDebug >= 3 {
-		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(c), uint(char))
+		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(token), uint(char))
 	}
-	return c
+	return char, token
 }
 
 func yyParse(yylex yyLexer) int {
+	return yyNewParser().Parse(yylex)
+}
+
+func (yyrcvr *yyParserImpl) Parse(yylex yyLexer) int {
 	var yyn int
-	var yylval yySymType
 	var yyVAL yySymType
-	yyS := make([]yySymType, yyMaxDepth)
+	var yyDollar []yySymType
+	_ = yyDollar // silence set and not used
+	yyS := yyrcvr.stack[:]
 
 	Nerrs := 0   /* number of errors */
 	Errflag := 0 /* error recovery flag */
 	yystate := 0
-	yychar := -1
+	yyrcvr.char = -1
+	yytoken := -1 // yyrcvr.char translated into internal numbering
+	defer func() {
+		// Make sure we report no lookahead when not parsing.
+		yystate = -1
+		yyrcvr.char = -1
+		yytoken = -1
+	}()
 	yyp := -1
 	goto yystack
 
@@ -269,7 +501,7 @@ ret1:
 yystack:
 	/* put a state and value onto the stack */
 	if yyDebug >= 4 {
-		__yyfmt__.Printf("char %v in %v\n", yyTokname(yychar), yyStatname(yystate))
+		__yyfmt__.Printf("char %v in %v\n", yyTokname(yytoken), yyStatname(yystate))
 	}
 
 	yyp++
@@ -282,21 +514,22 @@ yystack:
 	yyS[yyp].yys = yystate
 
 yynewstate:
-	yyn = yyPact[yystate]
+	yyn = int(yyPact[yystate])
 	if yyn <= yyFlag {
 		goto yydefault /* simple state */
 	}
-	if yychar < 0 {
-		yychar = yylex1(yylex, &yylval)
+	if yyrcvr.char < 0 {
+		yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
 	}
-	yyn += yychar
+	yyn += yytoken
 	if yyn < 0 || yyn >= yyLast {
 		goto yydefault
 	}
-	yyn = yyAct[yyn]
-	if yyChk[yyn] == yychar { /* valid shift */
-		yychar = -1
-		yyVAL = yylval
+	yyn = int(yyAct[yyn])
+	if int(yyChk[yyn]) == yytoken { /* valid shift */
+		yyrcvr.char = -1
+		yytoken = -1
+		yyVAL = yyrcvr.lval
 		yystate = yyn
 		if Errflag > 0 {
 			Errflag--
@@ -306,27 +539,27 @@ yynewstate:
 
 yydefault:
 	/* default state action */
-	yyn = yyDef[yystate]
+	yyn = int(yyDef[yystate])
 	if yyn == -2 {
-		if yychar < 0 {
-			yychar = yylex1(yylex, &yylval)
+		if yyrcvr.char < 0 {
+			yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
 		}
 
 		/* look through exception table */
 		xi := 0
 		for {
-			if yyExca[xi+0] == -1 && yyExca[xi+1] == yystate {
+			if yyExca[xi+0] == -1 && int(yyExca[xi+1]) == yystate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			yyn = yyExca[xi+0]
-			if yyn < 0 || yyn == yychar {
+			yyn = int(yyExca[xi+0])
+			if yyn < 0 || yyn == yytoken {
 				break
 			}
 		}
-		yyn = yyExca[xi+1]
+		yyn = int(yyExca[xi+1])
 		if yyn < 0 {
 			goto ret0
 		}
@@ -335,11 +568,11 @@ yydefault:
 		/* error ... attempt to resume parsing */
 		switch Errflag {
 		case 0: /* brand new error */
-			yylex.Error("syntax error")
+			yylex.Error(yyErrorMessage(yystate, yytoken))
 			Nerrs++
 			if yyDebug >= 1 {
 				__yyfmt__.Printf("%s", yyStatname(yystate))
-				__yyfmt__.Printf(" saw %s\n", yyTokname(yychar))
+				__yyfmt__.Printf(" saw %s\n", yyTokname(yytoken))
 			}
 			fallthrough
 
@@ -348,10 +581,10 @@ yydefault:
 
 			/* find a state where "error" is a legal shift action */
 			for yyp >= 0 {
-				yyn = yyPact[yyS[yyp].yys] + yyErrCode
+				yyn = int(yyPact[yyS[yyp].yys]) + yyErrCode
 				if yyn >= 0 && yyn < yyLast {
-					yystate = yyAct[yyn] /* simulate a shift of "error" */
-					if yyChk[yystate] == yyErrCode {
+					yystate = int(yyAct[yyn]) /* simulate a shift of "error" */
+					if int(yyChk[yystate]) == yyErrCode {
 						goto yystack
 					}
 				}
@@ -367,12 +600,13 @@ yydefault:
 
 		case 3: /* no shift yet; clobber input char */
 			if yyDebug >= 2 {
-				__yyfmt__.Printf("error recovery discards %s\n", yyTokname(yychar))
+				__yyfmt__.Printf("error recovery discards %s\n", yyTokname(yytoken))
 			}
-			if yychar == yyEofCode {
+			if yytoken == yyEofCode {
 				goto ret1
 			}
-			yychar = -1
+			yyrcvr.char = -1
+			yytoken = -1
 			goto yynewstate /* try again in the same state */
 		}
 	}
@@ -386,172 +620,332 @@ yydefault:
 	yypt := yyp
 	_ = yypt // guard against "declared and not used"
 
-	yyp -= yyR2[yyn]
+	yyp -= int(yyR2[yyn])
+	// yyp is now the index of $0. Perform the default action. Iff the
+	// reduced production is ε, $1 is possibly out of range.
+	if yyp+1 >= len(yyS) {
+		nyys := make([]yySymType, len(yyS)*2)
+		copy(nyys, yyS)
+		yyS = nyys
+	}
 	yyVAL = yyS[yyp+1]
 
 	/* consult goto table to find next state */
-	yyn = yyR1[yyn]
-	yyg := yyPgo[yyn]
+	yyn = int(yyR1[yyn])
+	yyg := int(yyPgo[yyn])
 	yyj := yyg + yyS[yyp].yys + 1
 
 	if yyj >= yyLast {
-		yystate = yyAct[yyg]
+		yystate = int(yyAct[yyg])
 	} else {
-		yystate = yyAct[yyj]
-		if yyChk[yystate] != -yyn {
-			yystate = yyAct[yyg]
+		yystate = int(yyAct[yyj])
+		if int(yyChk[yystate]) != -yyn {
+			yystate = int(yyAct[yyg])
 		}
 	}
 	// dummy call; replaced with literal code
 	switch yynt {
 
 	case 1:
-		//line rule.y:32
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line rule.y:34
+		{
+			yylex.(*ruleLexer).grammer = yyDollar[2].grammer
+		}
+	case 3:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line rule.y:36
+		{
+		}
+	case 4:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line rule.y:37
+		{
+			yylex.(*ruleLexer).bind(yyDollar[2].str, yyDollar[4].factor)
+		}
+	case 5:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line rule.y:38
 		{
-			g_mutex.Lock()
-			g_grammer = yyS[yypt-0].grammer
-			g_mutex.Unlock()
+			yylex.(*ruleLexer).bind(yyDollar[2].str, yyDollar[4].expr)
 		}
-	case 2:
-		//line rule.y:33
+	case 6:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line rule.y:39
+		{
+			yylex.(*ruleLexer).load(yyDollar[2].str, "", false)
+		}
+	case 7:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line rule.y:40
+		{
+			yylex.(*ruleLexer).load(yyDollar[2].str, yyDollar[4].str, false)
+		}
+	case 8:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line rule.y:41
+		{
+			yylex.(*ruleLexer).load(yyDollar[2].str, "", true)
+		}
+	case 9:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line rule.y:43
 		{
 			var err error
-			if yyVAL.grammer, err = NewGrammer(EGET, yyS[yypt-3].expr, yyS[yypt-1].dval, yyS[yypt-0].grammer); err != nil {
+			if yyVAL.grammer, err = NewGrammer(EGET, yyDollar[1].expr, yyDollar[3].dval, yyDollar[4].grammer); err != nil {
 				panic(err)
 			}
 		}
-	case 3:
-		//line rule.y:34
+	case 10:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line rule.y:44
 		{
 			var err error
-			if yyVAL.grammer, err = NewGrammer(DGET, nil, yyS[yypt-1].dval, yyS[yypt-0].grammer); err != nil {
+			if yyVAL.grammer, err = NewGrammer(DGET, nil, yyDollar[3].dval, yyDollar[4].grammer); err != nil {
 				panic(err)
 			}
 		}
-	case 4:
-		//line rule.y:35
+	case 11:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line rule.y:45
 		{
 			var err error
-			if yyVAL.grammer, err = NewGrammer(EEXPR, yyS[yypt-1].expr, 0, yyS[yypt-0].grammer); err != nil {
+			if yyVAL.grammer, err = NewGrammer(EEXPR, yyDollar[1].expr, 0, yyDollar[2].grammer); err != nil {
 				panic(err)
 			}
 		}
-	case 5:
-		//line rule.y:36
+	case 12:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line rule.y:46
 		{
 			yyVAL.grammer = nil
 		}
-	case 6:
-		//line rule.y:38
+	case 13:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line rule.y:48
 		{
 			var err error
-			if yyVAL.expr, err = NewExpr(AND, yyS[yypt-2].expr, yyS[yypt-0].term); err != nil {
+			if yyVAL.expr, err = NewExpr(AND, yyDollar[1].expr, yyDollar[3].term); err != nil {
 				panic(err)
 			}
 		}
-	case 7:
-		//line rule.y:39
+	case 14:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line rule.y:49
 		{
 			var err error
-			if yyVAL.expr, err = NewExpr(OR, yyS[yypt-2].expr, yyS[yypt-0].term); err != nil {
+			if yyVAL.expr, err = NewExpr(OR, yyDollar[1].expr, yyDollar[3].term); err != nil {
 				panic(err)
 			}
 		}
-	case 8:
-		//line rule.y:40
+	case 15:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:50
 		{
 			var err error
-			if yyVAL.expr, err = NewExpr(TERM, nil, yyS[yypt-0].term); err != nil {
+			if yyVAL.expr, err = NewExpr(TERM, nil, yyDollar[1].term); err != nil {
 				panic(err)
 			}
 		}
-	case 9:
-		//line rule.y:42
+	case 16:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line rule.y:52
 		{
 			var err error
-			if yyVAL.term, err = NewTerm(TKind_t(yyS[yypt-3].fn), yyS[yypt-4].factor, yyS[yypt-1].list, nil, nil); err != nil {
+			yylex.(*ruleLexer).checkList(yyDollar[4].list)
+			if yyVAL.term, err = NewTerm(TKind_t(yyDollar[2].fn), yyDollar[1].factor, yyDollar[4].list, nil, nil); err != nil {
 				panic(err)
 			}
+			yylex.(*ruleLexer).checkTerm(yyVAL.term)
 		}
-	case 10:
-		//line rule.y:43
+	case 17:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line rule.y:53
 		{
 			var err error
-			if yyVAL.term, err = NewTerm(TKind_t(yyS[yypt-1].fn), yyS[yypt-2].factor, nil, yyS[yypt-0].factor, nil); err != nil {
+			if yyVAL.term, err = NewTerm(TKind_t(yyDollar[2].fn), yyDollar[1].factor, nil, yyDollar[3].factor, nil); err != nil {
 				panic(err)
 			}
+			yylex.(*ruleLexer).checkTerm(yyVAL.term)
 		}
-	case 11:
-		//line rule.y:44
+	case 18:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line rule.y:54
 		{
 			var err error
-			if yyVAL.term, err = NewTerm(EXPR, nil, nil, nil, yyS[yypt-1].expr); err != nil {
+			if yyVAL.term, err = NewTerm(EXPR, nil, nil, nil, yyDollar[2].expr); err != nil {
 				panic(err)
 			}
 		}
-	case 12:
-		//line rule.y:46
+	case 19:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line rule.y:55
 		{
 			var err error
-			if yyVAL.factor, err = NewFactor(VARIABLE, 0, "", yyS[yypt-0].str, nil); err != nil {
+			if yyVAL.term, err = NewTerm(TKind_t(yyDollar[1].fn), yyDollar[3].factor, nil, nil, yyDollar[5].expr); err != nil {
 				panic(err)
 			}
 		}
-	case 13:
-		//line rule.y:47
+	case 20:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:56
 		{
 			var err error
-			if yyVAL.factor, err = NewFactor(STRING, 0, yyS[yypt-0].str, "", nil); err != nil {
+			var f *Factor
+			if f, err = NewFactor(FUNCTION, 0, "", "", yyDollar[1].fun); err != nil {
+				panic(err)
+			}
+			if yyVAL.term, err = NewTerm(BOOL, f, nil, nil, nil); err != nil {
 				panic(err)
 			}
 		}
-	case 14:
-		//line rule.y:48
+	case 21:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:57
 		{
 			var err error
-			if yyVAL.factor, err = NewFactor(DOUBLE, yyS[yypt-0].dval, "", "", nil); err != nil {
+			var f *Factor
+			yylex.(*ruleLexer).checkRef(yyDollar[1].str)
+			if f, err = NewFactor(VARIABLE, 0, "", yyDollar[1].str, nil); err != nil {
+				panic(err)
+			}
+			if yyVAL.term, err = NewTerm(BOOL, f, nil, nil, nil); err != nil {
 				panic(err)
 			}
 		}
-	case 15:
-		//line rule.y:49
+	case 22:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:59
 		{
 			var err error
-			if yyVAL.factor, err = NewFactor(FUNCTION, 0, "", "", yyS[yypt-0].fun); err != nil {
+			if yyVAL.factor, err = NewFactor(VARIABLE, 0, "", yyDollar[1].str, nil); err != nil {
 				panic(err)
 			}
 		}
-	case 16:
-		//line rule.y:51
+	case 23:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:60
 		{
 			var err error
-			if yyVAL.list, err = NewList(yyS[yypt-0].factor, nil); err != nil {
+			if yyVAL.factor, err = NewFactor(STRING, 0, yyDollar[1].str, "", nil); err != nil {
 				panic(err)
 			}
 		}
-	case 17:
-		//line rule.y:52
+	case 24:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:61
 		{
 			var err error
-			if yyVAL.list, err = NewList(yyS[yypt-2].factor, yyS[yypt-0].list); err != nil {
+			if yyVAL.factor, err = NewFactor(DOUBLE, yyDollar[1].dval, "", "", nil); err != nil {
 				panic(err)
 			}
 		}
-	case 18:
-		//line rule.y:54
+	case 25:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:62
 		{
 			var err error
-			if yyVAL.fun, err = NewFunc(FnKind_t(yyS[yypt-3].fn), yyS[yypt-1].list); err != nil {
+			if yyVAL.factor, err = NewIntegerFactor(yyDollar[1].ival); err != nil {
 				panic(err)
 			}
 		}
-	case 19:
-		//line rule.y:55
+	case 26:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:63
+		{
+			var err error
+			yylex.(*ruleLexer).checkParam(yyDollar[1].str)
+			if yyVAL.factor, err = NewFactor(VARIABLE, 0, "", "$"+yyDollar[1].str, nil); err != nil {
+				panic(err)
+			}
+		}
+	case 27:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:64
+		{
+			var err error
+			if yyVAL.factor, err = NewFactor(FUNCTION, 0, "", "", yyDollar[1].fun); err != nil {
+				panic(err)
+			}
+		}
+	case 28:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:66
+		{
+			yyVAL.dval = yyDollar[1].dval
+		}
+	case 29:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:67
+		{
+			yyVAL.dval = float64(yyDollar[1].ival)
+		}
+	case 30:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line rule.y:69
+		{
+			var err error
+			if yyVAL.list, err = NewList(yyDollar[1].factor, nil); err != nil {
+				panic(err)
+			}
+		}
+	case 31:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line rule.y:70
+		{
+			var err error
+			if yyVAL.list, err = NewList(yyDollar[1].factor, yyDollar[3].list); err != nil {
+				panic(err)
+			}
+		}
+	case 32:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line rule.y:72
+		{
+			var err error
+			if yyVAL.fun, err = NewFunc(FnKind_t(yyDollar[1].fn), yyDollar[3].list); err != nil {
+				panic(err)
+			}
+			yylex.(*ruleLexer).checkFunc(yyVAL.fun)
+		}
+	case 33:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line rule.y:73
+		{
+			var err error
+			if yyVAL.fun, err = NewFunc(FnKind_t(yyDollar[1].fn), nil); err != nil {
+				panic(err)
+			}
+		}
+	case 34:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line rule.y:74
+		{
+			var err error
+			var f *Factor
+			yylex.(*ruleLexer).checkList(yyDollar[6].list)
+			if f, err = NewListFactor(yyDollar[6].list); err != nil {
+				panic(err)
+			}
+			if yyVAL.fun, err = NewFunc(FnKind_t(yyDollar[1].fn), &List{yyDollar[3].factor, &List{f, nil}}); err != nil {
+				panic(err)
+			}
+			yylex.(*ruleLexer).checkFunc(yyVAL.fun)
+		}
+	case 35:
+		yyDollar = yyS[yypt-10 : yypt+1]
+//line rule.y:75
 		{
 			var err error
-			if yyVAL.fun, err = NewFunc(FnKind_t(yyS[yypt-2].fn), nil); err != nil {
+			var f *Factor
+			yylex.(*ruleLexer).checkList(yyDollar[6].list)
+			if f, err = NewListFactor(yyDollar[6].list); err != nil {
+				panic(err)
+			}
+			if yyVAL.fun, err = NewFunc(FnKind_t(yyDollar[1].fn), &List{yyDollar[3].factor, &List{f, yyDollar[9].list}}); err != nil {
 				panic(err)
 			}
+			yylex.(*ruleLexer).checkFunc(yyVAL.fun)
 		}
 	}
 	goto yystack /* stack new state and value */
@@ -1,103 +1,1537 @@
 package filter
 
 import (
-	"fmt"
-	"io/ioutil"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
 	"math/rand"
+	"mime/multipart"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
-var samples = []string{"condition", "false", "function", "query", "true", "abnormal"}
+func TestClock(t *testing.T) {
+	now := time.Date(2014, 9, 13, 10, 30, 0, 0, time.FixedZone("CST", 8*3600))
+	clock := func() time.Time { return now }
+	cases := []struct {
+		rule   string
+		input  string
+		expect int
+	}{
+		{"unix() == 1410575400", "x=1", 1},
+		{"now() > 1410575399.5 && now() < 1410575400.5", "x=1", 1},
+		{"since(unix(ts)) > duration('5m') => 1; default => 0", "ts=1410575000", 1},
+		{"since(unix(ts)) > duration('5m') => 1; default => 0", "ts=1410575300", 0},
+		{"until(ts) == 60", `{"ts":1410575460}`, 1},
+		{"hour(now()) >= 9 && hour(now()) < 18", "x=1", 1},
+		{"hour() == 10 && weekday() == 6", "x=1", 1},
+		{"hour(unix(ts)) == 8 && hour(ts) == 8", `{"ts":1410566400}`, 1},
+		{"parse_time(t, 'DateTime') == 1410575400", "t=2014-09-13 10:30:00", 1},
+		{"parse_time(t, 'RFC3339') == 1410575400", "t=2014-09-13T02:30:00Z", 1},
+		{"parse_time(t, '02/01/2006') < now()", "t=12/09/2014", 1},
+		{"duration('1h30m') == 5400", "x=1", 1},
+		{"time_add(unix(ts), duration('5m')) < now()", "ts=1410575000", 1},
+		{"time_add(unix(ts), duration('-1h')) == 1410571800 && time_add(duration('1h'), 60) == 3660", "ts=1410575400", 1},
+		{"time_sub(now(), parse_time(t, 'DateTime')) == duration('1h')", "t=2014-09-13 09:30:00", 1},
+		{"time_sub(ts, now()) < 0 && time_sub(duration('1h'), duration('30m')) == 1800", `{"ts":1410575000}`, 1},
+	}
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule), WithClock(clock))
+		if err != nil {
+			t.Errorf("case %d: %s: %v", i, c.rule, err)
+			continue
+		}
+		var symlist *SymList
+		if c.input[0] == '{' {
+			symlist, _ = JsonToSymlist(c.input)
+		} else {
+			symlist, _ = QueryToSymlist(c.input)
+		}
+		actual, err := h.Parse(symlist)
+		if err != nil {
+			t.Errorf("case %d: %s: %v", i, c.rule, err)
+		} else if actual != c.expect {
+			t.Errorf("case %d: %s: expect %d, actual %d", i, c.rule, c.expect, actual)
+		}
+	}
+
+	// strings are numbers only for unix() or as WithCoercion() says
+	errs := []struct {
+		rule   string
+		opts   []Option
+		expect string
+	}{
+		{"hour(ts) == 8", nil, "hour() parameter should be 'double'"},
+		{"since(ts) > 0", nil, "since() parameter should be 'double'"},
+		{"time_add(ts, 60) > 0", []Option{WithCoercion(STRICT)}, "time_add() parameter should be 'double'"},
+		{"hour(bad) == 8", []Option{WithCoercion(NUMERIC)}, "hour() parameter 'x' is not a number"},
+		{"unix(bad) > 0", nil, "unix() parameter should be 'double'"},
+		{"unix(nan) > 0", nil, "unix() parameter should be 'double'"},
+	}
+	symlist, _ := QueryToSymlist("ts=1410566400&bad=x&nan=NaN")
+	for _, c := range errs {
+		h, err := NewParser(strings.NewReader(c.rule), append(c.opts, WithClock(clock))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := h.Parse(symlist); err == nil || err.Error() != c.expect {
+			t.Errorf("%s: expect error %q, actual %v", c.rule, c.expect, err)
+		}
+	}
+	for _, opts := range [][]Option{nil, {WithCoercion(STRICT)}, {WithCoercion(NUMERIC)}} {
+		h, _ := NewParser(strings.NewReader("hour(unix(ts)) == 8"), append(opts, WithClock(clock))...)
+		if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+			t.Errorf("unix() of a string: expect 1, actual %d %v", ret, err)
+		}
+	}
+	h, _ := NewParser(strings.NewReader("hour(ts) == 8"), WithCoercion(NUMERIC), WithClock(clock))
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("hour() of a numeric string: expect 1, actual %d %v", ret, err)
+	}
+}
+
+func TestRate(t *testing.T) {
+	now := time.Date(2014, 9, 13, 10, 30, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	store := NewMemoryStore(0)
+	rate, err := NewParser(strings.NewReader("rate(ip, 3, '1m') => 429; default => 0"),
+		WithClock(clock), WithStateStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	distinct, err := NewParser(strings.NewReader("count_distinct(uid, device, '1h') > 2 => 1"),
+		WithClock(clock), WithStateStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
 
-func TestFilter(t *testing.T) {
-	fmt.Println("[!!NOTICE!!] IGNORE the error report if file name is 'abnormal'")
-	for _, file := range samples {
-		buf, err := ioutil.ReadFile("./sample/" + file)
+	parse := func(h *Parser, query string) int {
+		symlist, _ := QueryToSymlist(query)
+		ret, err := h.Parse(symlist)
 		if err != nil {
 			t.Fatal(err)
 		}
-		lines := strings.Split(string(buf), "\n")
-		for i, line := range lines {
-			if len(line) == 0 || line[0] == '\n' || line[0] == '/' {
-				continue
+		return ret
+	}
+
+	for i, expect := range []int{0, 0, 0, 429, 429} {
+		if actual := parse(rate, "ip=10.0.0.1"); actual != expect {
+			t.Errorf("hit %d: expect %d, actual %d", i+1, expect, actual)
+		}
+	}
+	if actual := parse(rate, "ip=10.0.0.2"); actual != 0 {
+		t.Errorf("other key: expect 0, actual %d", actual)
+	}
+	now = now.Add(2 * time.Minute)
+	if actual := parse(rate, "ip=10.0.0.1"); actual != 0 {
+		t.Errorf("after window: expect 0, actual %d", actual)
+	}
+
+	for i, device := range []string{"a", "b", "a", "c", "d"} {
+		expect := bool2int(i >= 3)
+		if actual := parse(distinct, "uid=7&device="+device); actual != expect {
+			t.Errorf("device %s: expect %d, actual %d", device, expect, actual)
+		}
+	}
+	now = now.Add(2 * time.Hour)
+	if actual := parse(distinct, "uid=7&device=e"); actual != 0 {
+		t.Errorf("after window: expect 0, actual %d", actual)
+	}
+
+	h, _ := NewParser(strings.NewReader("rate(ip, 3, '1m') => 429"))
+	symlist, _ := QueryToSymlist("ip=10.0.0.1")
+	if _, err := h.Parse(symlist); err == nil {
+		t.Error("rate() without a state store should fail")
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2014, 9, 13, 10, 30, 0, 0, time.UTC)
+	store := NewMemoryStore(10)
+	for i := 0; i < 100; i++ {
+		store.Hit(strconv.Itoa(i), time.Minute, now)
+	}
+	if store.Len() != 10 {
+		t.Errorf("expect 10 keys, actual %d", store.Len())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				store.Hit("shared", time.Minute, now)
 			}
-			v := strings.Split(line, "%")
-			if len(v) != 3 {
-				continue
+		}()
+	}
+	wg.Wait()
+	if hits, _ := store.Hit("shared", time.Minute, now); hits != 801 {
+		t.Errorf("expect 801 hits, actual %v", hits)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	cases := []struct {
+		rule   string
+		input  string
+		opt    Option
+		budget string
+	}{
+		{"x @ ('1','2','3','4','5','6','7','8','9') => 1", "x=9", WithMaxSteps(8), "steps"},
+		{"md5(x,x,x,x,x,x,x,x,x,x) == 'abc'", "x=1", WithMaxSteps(8), "steps"},
+		{"((((((x == '1'))))))", "x=1", WithMaxDepth(8), "depth"},
+		{"len(md5(md5(md5(md5(x))))) == 32", "x=1", WithMaxDepth(6), "depth"},
+		{"x # '^a+$'", "x=" + strings.Repeat("a", 1024), WithMaxValueSize(1000), "value size"},
+		{"len(x,x) > 0", "x=" + strings.Repeat("a", 600), WithMaxValueSize(1000), ""},
+		{"md5(x,x) == 'abc'", "x=" + strings.Repeat("a", 600), WithMaxValueSize(1000), "value size"},
+	}
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule), c.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		symlist, _ := QueryToSymlist(c.input)
+		_, err = h.Parse(symlist)
+		var budget *BudgetExceeded
+		if c.budget == "" {
+			if err != nil {
+				t.Errorf("case %d: %s: %v", i, c.rule, err)
 			}
-			h, err := NewParser(strings.NewReader(v[1]))
+		} else if !errors.As(err, &budget) || budget.Budget != c.budget {
+			t.Errorf("case %d: %s: expect %s budget exceeded, actual %v", i, c.rule, c.budget, err)
+		}
+	}
+
+	h, _ := NewParser(strings.NewReader("x == '1' => 1"))
+	symlist, _ := QueryToSymlist("x=1")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if ret, err := h.ParseContext(ctx, symlist); ret != -1 || err != context.Canceled {
+		t.Errorf("canceled: expect -1 and %v, actual %d and %v", context.Canceled, ret, err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if ret, err := h.ParseContext(ctx, symlist); ret != 1 || err != nil {
+		t.Errorf("expect 1, actual %d and %v", ret, err)
+	}
+}
+
+func TestCompileLimits(t *testing.T) {
+	cases := []struct {
+		rule   string
+		opt    Option
+		line   int
+		column int
+	}{
+		{"x == '1'", WithMaxSourceSize(10), 0, 0},
+		{strings.Repeat("x == '1' ", 10), WithMaxSourceSize(50), -1, -1},
+		{strings.Repeat("x == '1' ", 10), WithMaxNodes(20), -1, -1},
+		{"x == '1' ", WithMaxNodes(20), 0, 0},
+		{"x == '1' &&\n ((x == '2'))", WithMaxNesting(1), 2, 3},
+		{"x @ ('1', '2', '3')", WithMaxListLen(2), 1, 19},
+		{"md5(x, x, x) == '1'", WithMaxListLen(2), 1, 12},
+		{"x @ ('1', '2')", WithMaxListLen(2), 0, 0},
+		{"x # 'a{100}'", WithMaxRegexSize(50), 1, 5},
+		{"x # 'a{10}'", WithMaxRegexSize(50), 0, 0},
+		{"x # 'a('", nil, 1, 5},
+		{"// 注释\nx == '张三' && ((y == '1'))", WithMaxNesting(1), 2, 15},
+		{"x == '张三'\t&& // a\n\n y == == '1'", nil, 3, 7},
+	}
+	for i, c := range cases {
+		opts := []Option{}
+		if c.opt != nil {
+			opts = append(opts, c.opt)
+		}
+		_, err := NewParser(strings.NewReader(c.rule), opts...)
+		var cerr *CompileError
+		if c.line == 0 {
 			if err != nil {
-				fmt.Println("file:", file, "line:", i+1, v, err)
-				continue
+				t.Errorf("case %d: %q: %v", i, c.rule, err)
 			}
+		} else if !errors.As(err, &cerr) {
+			t.Errorf("case %d: %q: expect a compile error, actual %v", i, c.rule, err)
+		} else if c.line > 0 && (cerr.Line != c.line || cerr.Column != c.column) {
+			t.Errorf("case %d: %q: expect line %d column %d, actual %v", i, c.rule, c.line, c.column, err)
+		}
+	}
+}
+
+func TestRegexSyntax(t *testing.T) {
+	cases := []struct {
+		rule   string
+		kind   RKind_t
+		symbol string
+		expect int
+	}{
+		// \d is not POSIX ERE
+		{"x # '^\\d+$'", PERL, "x=123", 1},
+		{"x #i '^(?:abc)+$'", PERL, "x=ABCabc", 1},
+		{"x # p", PERL, "x=a1&p=\\d", 1},
+		{"x # p", POSIX, "x=a1&p=\\d", -1},
+		{"x # 'a|ab'", POSIX, "x=ab", 1},
+		{"x #i '^AB$'", POSIX, "x=ab", 1},
+	}
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule), WithRegexSyntax(c.kind))
+		if err != nil {
+			t.Errorf("case %d: %q: %v", i, c.rule, err)
+			continue
+		}
+		symlist, _ := QueryToSymlist(c.symbol)
+		if ret, _ := h.Parse(symlist); ret != c.expect {
+			t.Errorf("case %d: %q: expect %d, actual %d", i, c.rule, c.expect, ret)
+		}
+	}
+
+	if _, err := NewParser(strings.NewReader("x # '^\\d+$'")); err == nil {
+		t.Errorf("expect '\\d' rejected by POSIX syntax")
+	}
+
+	// patterns from the input are compiled once and reused
+	h, _ := NewParser(strings.NewReader("x # p"), WithMaxRegexSize(50))
+	for i := 0; i < 3; i++ {
+		symlist, _ := QueryToSymlist("x=abc&p=b")
+		if ret, err := h.Parse(symlist); ret != 1 || err != nil {
+			t.Errorf("expect 1, actual %d and %v", ret, err)
+		}
+	}
+	if n := len(h.regexes.entries); n != 1 {
+		t.Errorf("expect 1 cached regex, actual %d", n)
+	}
+	symlist, _ := QueryToSymlist("x=abc&p=b{100}")
+	if ret, err := h.Parse(symlist); ret != -1 || err == nil {
+		t.Errorf("expect regex size error, actual %d and %v", ret, err)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	cases := []struct {
+		glob   string
+		value  string
+		expect bool
+	}{
+		{"*", "", true},
+		{"*", "a/b", false},
+		{"**", "a/b", true},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "ab", false},
+		{"a**b", "a/x/b", true},
+		{"[a/]", "/", false},
+		{"[!a]", "/", false},
+		{"[]]", "]", true},
+		{"[^]]", "]", false},
+		{"x[!.]y", "x-y", true},
+		{"?", "é", true},
+		{"[é]", "é", true},
+		{"(a|b)+", "(a|b)+", true},
+		{"a\nb", "a\nb", true},
+	}
+	for i, c := range cases {
+		regex, err := CompileGlob(c.glob)
+		if err != nil {
+			t.Errorf("case %d: %q: %v", i, c.glob, err)
+		} else if rc := regex.MatchString(c.value); rc != c.expect {
+			t.Errorf("case %d: %q ~ %q: expect %v, actual %v", i, c.value, c.glob, c.expect, rc)
+		}
+	}
+
+	for _, glob := range []string{"[abc", "abc\\"} {
+		if _, err := CompileGlob(glob); err == nil {
+			t.Errorf("%q: expect an error", glob)
+		}
+	}
+	if _, err := NewParser(strings.NewReader("x ~ y")); err == nil {
+		t.Errorf("expect glob of a variable rejected")
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	keywords := []string{"he", "she", "his", "hers", "ab", "bab", "aab", "b"}
+	m, err := NewMatcher(keywords, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compare with searching each keyword on its own
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		b := make([]byte, r.Intn(12))
+		for j := range b {
+			b[j] = "abehrsi"[r.Intn(7)]
+		}
+		s := string(b)
+
+		expect, end := "", len(s)+1
+		for _, kw := range keywords {
+			if j := strings.Index(s, kw); j >= 0 {
+				if e := j + len(kw); e < end || (e == end && len(kw) > len(expect)) {
+					expect, end = kw, e
+				}
+			}
+		}
+		if kw, ok := m.Find(s); kw != expect || ok != (expect != "") {
+			t.Errorf("%q: expect %q, actual %q", s, expect, kw)
+		}
+	}
+
+	m, _ = NewMatcher([]string{"Select", "ÉTÉ"}, true)
+	if kw, _ := m.Find("un SELECT"); kw != "Select" {
+		t.Errorf("expect 'Select', actual %q", kw)
+	}
+	if kw, _ := m.Find("un été"); kw != "ÉTÉ" {
+		t.Errorf("expect 'ÉTÉ', actual %q", kw)
+	}
+}
+
+func TestArraySymlist(t *testing.T) {
+	symlist, _ := QueryToSymlist("tag=a&x=1&tag=b&tag=c")
+	if v, err := SymbolLookup(symlist, "tag"); err != nil || v.Kind != STRING || v.Value.(string) != "a" {
+		t.Errorf("expect the first value 'a', actual %v and %v", v, err)
+	}
+	if v, err := SymbolLookup(symlist, "x"); err != nil || v.Kind != STRING {
+		t.Errorf("expect a string, actual %v and %v", v, err)
+	}
+	// a repeated key compares, hashes and matches by its first value
+	h, _ := NewParser(strings.NewReader("tag == 'a' && tag # '^a$' && md5(tag) == md5('a') && " +
+		"size(tag) == 3 && all(tag, it @ ('a', 'b', 'c')) && count() == 2"))
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+
+	symlist, _ = JsonToSymlist(`{"a":{"b":{"c":1.5}},"l":[1.5,"x",{"k":"v"},[2],true,null]}`)
+	if v, err := SymbolLookup(symlist, "a.b.c"); err != nil || v.Value.(float64) != 1.5 {
+		t.Errorf("expect 1, actual %v and %v", v, err)
+	}
+	v, err := SymbolLookup(symlist, "l")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := v.Value.([]*SymList)
+	if len(items) != 6 || items[0].Kind != DOUBLE || items[1].Kind != STRING ||
+		items[2].Name != "k" || items[3].Kind != ARRAY || items[4].Kind != ARRAY || items[5].Kind != ARRAY {
+		t.Errorf("unexpected items %v", items)
+	}
+	// nested objects and arrays are symbols, counted by count()
+	symlist, _ = JsonToSymlist(`{"a":{"b":1,"c":2},"l":[1],"t":true,"n":null}`)
+	h, _ = NewParser(strings.NewReader("count() == 3"))
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("expect count() 3, actual %d %v", ret, err)
+	}
+
+	// elements without value keep their place
+	symlist, _ = JsonToSymlist(`{"l":[{}, 2], "m":[true, null, {"x":null}, "a"]}`)
+	h, _ = NewParser(strings.NewReader("any(l, it > 1) && size(m) == 4 && none(m, it == 'x') && any(m, it == 'a')"))
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+
+	// every item is a step
+	h, _ = NewParser(strings.NewReader("all(l, it > 0)"), WithMaxSteps(100))
+	symlist, _ = JsonToSymlist(`{"l":[` + strings.TrimSuffix(strings.Repeat("1,", 200), ",") + `]}`)
+	var budget *BudgetExceeded
+	if ret, err := h.Parse(symlist); ret != -1 || !errors.As(err, &budget) {
+		t.Errorf("expect budget exceeded, actual %d and %v", ret, err)
+	}
+}
+
+func TestBinding(t *testing.T) {
+	// rate() counts every evaluation: evaluated more than once, the first
+	// parse would already be over the limit
+	store := NewMemoryStore(0)
+	h, err := NewParser(strings.NewReader(
+		"let hot = rate(ip, 1, '1m'); hot == 1 => 2; hot == 1 => 3; hot == 0 => 1"),
+		WithStateStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expect := range []int{1, 2, 2} {
+		symlist, _ := QueryToSymlist("ip=10.0.0.1")
+		if ret, err := h.Parse(symlist); ret != expect || err != nil {
+			t.Errorf("parse %d: expect %d, actual %d and %v", i, expect, ret, err)
+		}
+	}
+
+	// unused bindings are not evaluated
+	h, _ = NewParser(strings.NewReader("let s = md5(missing); x == '1'"))
+	symlist, _ := QueryToSymlist("x=1")
+	if ret, err := h.Parse(symlist); ret != 1 || err != nil {
+		t.Errorf("expect 1, actual %d and %v", ret, err)
+	}
+
+	if _, err := NewParser(strings.NewReader("let s = md5(x)")); err == nil {
+		t.Errorf("expect a rule with no statement rejected")
+	}
+
+	// cycles are rejected at the declaration closing them, unused or not
+	var cerr *CompileError
+	_, err = NewParser(strings.NewReader("let a = md5(b);\ndef c = any(l, it == a);\nlet b = itoa(len(c)); x == '1'"))
+	if !errors.As(err, &cerr) || cerr.Line != 3 || cerr.Err != "'b' refers to itself: b -> c -> a -> b" {
+		t.Errorf("expect a cycle at line 3, actual %v", err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	fsys := fstest.MapFS{
+		"common.rules": {Data: []byte(`
+			import 'lib/bots.rules'
+			def internal = ip # '^10\.'
+			let salt = '@163.com'
+			def signed = md5(key, salt) == sig`)},
+		"lib/bots.rules": {Data: []byte(`
+			let pattern = 'bot|spider'
+			def is_bot = ua # pattern`)},
+		"cycle/a.rules":      {Data: []byte(`import 'b.rules'`)},
+		"cycle/b.rules":      {Data: []byte(`import 'a.rules'`)},
+		"bad.rules":          {Data: []byte("def ok = x == 1\ndef bad = x ==")},
+		"stmt.rules":         {Data: []byte(`x == 1 => 2`)},
+		"my-lib.rules":       {Data: []byte(`def yes = 1 == 1`)},
+		"salt.rules":         {Data: []byte(`let salt = 'other'`)},
+		"diamond/base.rules": {Data: []byte(`let limit = 'm'`)},
+		"diamond/a.rules":    {Data: []byte("include 'base.rules'\ndef low = n < limit")},
+		"diamond/b.rules":    {Data: []byte("include 'base.rules'\ndef high = n > limit")},
+	}
+
+	cases := []struct {
+		rule   string
+		query  string
+		expect int
+	}{
+		{"import 'common.rules'; common.internal => 1; default => 0", "ip=10.1.1.1", 1},
+		{"import 'common.rules'; common.internal => 1; default => 0", "ip=192.168.1.1", 0},
+		{"import 'common.rules'; common.bots.is_bot", "ua=Googlebot", 1},
+		{"import 'lib/bots.rules' as b; b.is_bot", "ua=Mozilla", 0},
+		{"include 'lib/bots.rules'; is_bot && pattern == 'bot|spider'", "ua=spider", 1},
+		{"import 'common.rules'; common.signed", "key=justhechuang&sig=4131bfb2bf25f5d9ef86ff9bf53e0055", 1},
+		// the salt of common.rules is not the one of the rule
+		{"import 'common.rules'; let salt = 'x'; common.signed && salt == 'x'", "key=justhechuang&sig=4131bfb2bf25f5d9ef86ff9bf53e0055", 1},
+		{"import 'my-lib.rules' as m; m.yes", "x=1", 1},
+		// both files include base.rules, its limit is declared once
+		{"include 'diamond/a.rules'; include 'diamond/b.rules'; low && high == 0 && limit == 'm'", "n=a", 1},
+		{"include 'diamond/a.rules'; import 'diamond/b.rules' as b; b.high && b.limit == limit", "n=x", 1},
+	}
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule), WithResolver(fsys))
+		if err != nil {
+			t.Errorf("case %d: %q: %v", i, c.rule, err)
+			continue
+		}
+		symlist, _ := QueryToSymlist(c.query)
+		if ret, err := h.Parse(symlist); ret != c.expect || err != nil {
+			t.Errorf("case %d: %q: expect %d, actual %d and %v", i, c.rule, c.expect, ret, err)
+		}
+	}
+
+	errs := []struct {
+		rule string
+		file string
+		line int
+	}{
+		{"import 'cycle/a.rules'; x == 1", "cycle/b.rules", 1},
+		{"import 'bad.rules'; x == 1", "bad.rules", 2},
+		{"import 'stmt.rules'; x == 1", "", 1},
+		{"import 'none.rules'; x == 1", "", 1},
+		{"import '../x.rules'; x == 1", "", 1},
+		{"import 'my-lib.rules'; x == 1", "", 1},
+		{"include 'common.rules'; include 'salt.rules'; x == 1", "", 1},
+		{"include 'diamond/a.rules'; let limit = 'n'; x == 1", "", 1},
+	}
+	for i, c := range errs {
+		_, err := NewParser(strings.NewReader(c.rule), WithResolver(fsys))
+		var cerr *CompileError
+		if !errors.As(err, &cerr) {
+			t.Errorf("case %d: %q: expect a compile error, actual %v", i, c.rule, err)
+		} else if cerr.File != c.file || cerr.Line != c.line {
+			t.Errorf("case %d: %q: expect file %q line %d, actual %v", i, c.rule, c.file, c.line, err)
+		}
+	}
+
+	if _, err := NewParser(strings.NewReader("import 'common.rules'; x == 1")); err == nil {
+		t.Errorf("expect import without resolver rejected")
+	}
+}
+
+func TestParams(t *testing.T) {
+	rule := "len(token) != $TOKEN_LEN => 1; name == $NAME => 2; x # $PATTERN => 3; default => 0"
+	h, err := NewParser(strings.NewReader(rule),
+		WithParams(map[string]interface{}{"TOKEN_LEN": 4, "NAME": "bob"}),
+		WithParams(map[string]interface{}{"PATTERN": "^a+$"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := h.Params(); strings.Join(names, ",") != "NAME,PATTERN,TOKEN_LEN" {
+		t.Errorf("unexpected params %v", names)
+	}
+
+	parse := func(h *Parser, query string) int {
+		symlist, _ := QueryToSymlist(query)
+		ret, _ := h.Parse(symlist)
+		return ret
+	}
+	if ret := parse(h, "token=abcd&name=bob&x=b"); ret != 2 {
+		t.Errorf("expect 2, actual %d", ret)
+	}
+	if ret := parse(h, "token=abc&name=bob&x=b"); ret != 1 {
+		t.Errorf("expect 1, actual %d", ret)
+	}
+
+	// the input can't pass for a parameter, a value is never rule text
+	h2, err := h.Rebind(map[string]interface{}{"TOKEN_LEN": 3.0, "NAME": "' || 1 == 1 || '"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret := parse(h2, "token=abc&name=alice&x=aaa&$NAME=alice"); ret != 3 {
+		t.Errorf("expect 3, actual %d", ret)
+	}
+	if ret := parse(h, "token=abc&name=bob&x=b"); ret != 1 {
+		t.Errorf("rebind changed the original parser, actual %d", ret)
+	}
+
+	if _, err := h.Rebind(map[string]interface{}{"NAME": true}); err == nil {
+		t.Errorf("expect a bool parameter rejected")
+	}
+	type port uint16
+	widths := map[string]interface{}{"I8": int8(-8), "I16": int16(16), "U8": uint8(8), "U16": port(443), "U64": uint64(math.MaxUint64)}
+	h3, err := NewParser(strings.NewReader("$I8 == -8 && $I16 == 16 && $U8 == 8 && $U16 == 443 && $U64 > 1e19"), WithParams(widths))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret, err := h3.Parse(nil); ret != 1 || err != nil {
+		t.Errorf("expect 1, actual %d and %v", ret, err)
+	}
+	_, err = NewParser(strings.NewReader("x == 1 && y == $MISSING"),
+		WithParams(map[string]interface{}{"OTHER": 1}))
+	var cerr *CompileError
+	if !errors.As(err, &cerr) || cerr.Column != 16 {
+		t.Errorf("expect a compile error at column 16, actual %v", err)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	for _, rule := range []string{"x == 1.2.3", "x == 1e", "x == 0x", "x == 12ab", "x == 99999999999999999999"} {
+		_, err := NewParser(strings.NewReader(rule))
+		var cerr *CompileError
+		if !errors.As(err, &cerr) || cerr.Column != 6 {
+			t.Errorf("%q: expect a compile error at column 6, actual %v", rule, err)
+		}
+	}
+
+	symlist, _ := JsonToSymlist(`{"x":3.14159}`)
+	h, err := NewParser(strings.NewReader("itoa(x, '%d%d') == '3'"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Parse(symlist); err == nil {
+		t.Errorf("expect an error for a format of two verbs")
+	}
+
+	// boolean builtins are integers
+	a, _ := NewFactor(STRING, 0, "a", "", nil)
+	list, _ := NewList(a, &List{Factor: a})
+	if v, err := EvalSecureEq(list, NewEnv(nil, nil)); err != nil || v.Kind != INTEGER || v.Value.(int64) != 1 {
+		t.Errorf("expect the integer 1, actual %v and %v", v, err)
+	}
+}
+
+func TestNumericEquality(t *testing.T) {
+	cases := []struct {
+		kind      QKind_t
+		tolerance float64
+		rule      string
+		input     string
+		expect    int
+	}{
+		{ABSOLUTE, 0.001, "price == 10.0005", `{"price":10}`, 1},
+		{ABSOLUTE, 0.5, "x == 10.5 && x >= 10.5 && x <= 10.5", `{"x":10}`, 1},
+		{ABSOLUTE, 0.5, "x == 10.75", `{"x":10}`, 0},
+		{ABSOLUTE, 0.5, "x < 10.25 || x > 10.25", `{"x":10}`, 0},
+		{ABSOLUTE, 0.5, "x != 10.5", `{"x":10}`, 0},
+		{ABSOLUTE, 0, "x == 10.0005", `{"x":10}`, 0},
+		{RELATIVE, 0.01, "x == 1010", `{"x":1000.0}`, 1},
+		{RELATIVE, 0.01, "x == 1011", `{"x":1000.0}`, 0},
+		{RELATIVE, 0.01, "x == 0.0001", `{"x":0.0}`, 0},
+		{EXACT, 0, "price == 10.0005", `{"price":10}`, 0},
+		{EXACT, 0, "price > 10 && price >= 10.0005 && price < 10.001", `{"price":10.0005}`, 1},
+		{EXACT, 0, "id == 1234567890123", `{"id":1234567890123.0}`, 1},
+		{EXACT, 0, "id == 9007199254740993", `{"id":9007199254740992.0}`, 0},
+		{EXACT, 0, "x @ (1, 2.5, 3)", `{"x":2.5}`, 1},
+		{EXACT, 0, "x @ (1, 2.5001, 3)", `{"x":2.5}`, 0},
+		{EXACT, 0, "x !@ (1, 2.5001, 3)", `{"x":2.5}`, 1},
+		{DECIMAL, 2, "x == 10.005", `{"x":10.01}`, 1},
+		{DECIMAL, 2, "x == 10.0049", `{"x":10}`, 1},
+		{DECIMAL, 2, "x > 10 || x < 10", `{"x":10.004}`, 0},
+		{DECIMAL, 2, "x == -10.005", `{"x":-10.01}`, 1},
+		{DECIMAL, 0, "x == 3", `{"x":2.5}`, 1},
+		{DECIMAL, 1, "x == 0.3", `{"x":0.30000000000000004}`, 1},
+		{DECIMAL, 2, "x @ (1.111, 2.226)", `{"x":2.23}`, 1},
+	}
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule), WithNumericEquality(c.kind, c.tolerance))
+		if err != nil {
+			t.Fatal(err)
+		}
+		symlist, _ := JsonToSymlist(c.input)
+		if ret, err := h.Parse(symlist); err != nil || ret != c.expect {
+			t.Errorf("case %d: %s %v %q: expect %d, actual %d %v", i, qkind2str(c.kind), c.tolerance, c.rule, c.expect, ret, err)
+		}
+	}
+
+	// without the option doubles compare as they always did
+	defaults := []struct {
+		rule   string
+		input  string
+		expect int
+	}{
+		{"price == 10.0005 && price >= 10.0005 && price <= 10.0005 && price != 10.0005", `{"price":10}`, 0},
+		{"price == 10.0005 && price >= 10.0005 && price <= 10.0005", `{"price":10}`, 1},
+		{"price > 10 && price < 10.001 && price == 10", `{"price":10.0005}`, 1},
+		{"x == 0.001 || x != 0.001", `{"x":0.0}`, 0},
+		{"x @ (1, 2.0005)", `{"x":2}`, 1},
+	}
+	for i, c := range defaults {
+		h, err := NewParser(strings.NewReader(c.rule))
+		if err != nil {
+			t.Fatal(err)
+		}
+		symlist, _ := JsonToSymlist(c.input)
+		if ret, err := h.Parse(symlist); err != nil || ret != c.expect {
+			t.Errorf("default %d: %q: expect %d, actual %d %v", i, c.rule, c.expect, ret, err)
+		}
+	}
+
+	for _, opt := range []Option{WithNumericEquality(ABSOLUTE, -1), WithNumericEquality(RELATIVE, math.NaN()),
+		WithNumericEquality(DECIMAL, 1.5), WithNumericEquality(DECIMAL, 19), WithNumericEquality(QKind_t(9), 0)} {
+		if _, err := NewParser(strings.NewReader("x == 1"), opt); err == nil {
+			t.Errorf("expect an invalid numeric equality rejected")
+		}
+	}
+}
+
+func TestCoercion(t *testing.T) {
+	cases := []struct {
+		kind   CKind_t
+		rule   string
+		expect int // for both inputs, -1 for an error
+	}{
+		{IGNORE, "gz == 10", -2},
+		{STRICT, "gz == 10", -2},
+		{NUMERIC, "gz == 10 && gz > 9 && gz != 11", 1},
+		{NUMERIC, "gz @ (5, 10) && gz !@ ('a', 11)", 1},
+		{NUMERIC, "name == 10 || name != 'bob'", 0},
+		{NUMERIC, "len(gz) == 2 && itoa(gz) == '10' && md5(gz) == md5('10')", 1},
+		{NUMERIC, "atoi(gz) == 10 && gz # '^1'", 1},
+		{NUMERIC, "md5(gz, 1) == md5('101') && sha1('x', 0.5) == sha1('x0.5')", 1},
+		{NUMERIC, "any(gz, it == 10)", 1},
+		{TEXT, "gz == 10 && gz == '10' && gz < '9'", 1},
+		{TEXT, "len(gz) == 2 && gz # '^10$'", 1},
+	}
+	inputs := []string{"gz=10&name=bob", `{"gz":10,"name":"bob"}`}
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule), WithCoercion(c.kind))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rets := []int{}
+		for _, input := range inputs {
 			var symlist *SymList
-			if v[2][0] == '{' {
-				symlist, _ = JsonToSymlist(v[2])
+			if input[0] == '{' {
+				symlist, _ = JsonToSymlist(input)
 			} else {
-				symlist, _ = QueryToSymlist(v[2])
+				symlist, _ = QueryToSymlist(input)
 			}
-			actual, err := h.Parse(symlist)
+			ret, err := h.Parse(symlist)
 			if err != nil {
-				fmt.Println("file:", file, "line:", i+1, "Parse error:", err)
-			} else {
-				expect, _ := strconv.Atoi(v[0])
-				if actual != expect {
-					t.Errorf("file: %s line: %d expect %d, actual %d\n", file, i+1, expect, actual)
-				}
+				ret = -1
 			}
+			rets = append(rets, ret)
+		}
+		if c.expect == -2 {
+			// the policies without coercion tell the inputs apart
+			if rets[1] != 1 || rets[0] != map[CKind_t]int{IGNORE: 0, STRICT: -1}[c.kind] {
+				t.Errorf("case %d: %s %q: unexpected %v", i, ckind2str(c.kind), c.rule, rets)
+			}
+		} else if rets[0] != c.expect || rets[1] != c.expect {
+			t.Errorf("case %d: %s %q: expect %d, actual %v", i, ckind2str(c.kind), c.rule, c.expect, rets)
+		}
+	}
+
+	for _, rule := range []string{"'10' == 10", "1 @ ('1', 2)"} {
+		_, err := NewParser(strings.NewReader(rule), WithCoercion(STRICT))
+		var cerr *CompileError
+		if !errors.As(err, &cerr) {
+			t.Errorf("%q: expect a compile error, actual %v", rule, err)
+		}
+		if _, err := NewParser(strings.NewReader(rule)); err != nil {
+			t.Errorf("%q: %v", rule, err)
 		}
 	}
 }
 
-func BenchmarkFilter(t *testing.B) {
-	rand.Seed(int64(time.Now().Second()))
-	buf, err := ioutil.ReadFile("./sample/bench")
+func TestForm(t *testing.T) {
+	symlist, err := FormToSymlist("name=bob+smith&note=a%26b&tag=x&tag=y&flag")
 	if err != nil {
 		t.Fatal(err)
 	}
-	// pre-store Parser handle and symbol list
-	var values [][]string
-	var handle []*Parser
-	lines := strings.Split(string(buf), "\n")
-	for _, line := range lines {
-		if len(line) == 0 || line[0] == '\n' || line[0] == '/' {
-			continue
+	h, _ := NewParser(strings.NewReader("name == 'bob smith' && note == 'a&b' && size(tag) == 2 && flag == ''"))
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+	if _, err := FormToSymlist("name=%zz"); err == nil {
+		t.Errorf("expect an error for a bad escape")
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("user", "bob")
+	f, _ := w.CreateFormFile("upload", "shell.php")
+	f.Write([]byte("<?php echo 1; ?>"))
+	f, _ = w.CreateFormFile("upload", "cat.jpg")
+	f.Write([]byte("jpeg"))
+	w.Close()
+	body, contentType := buf.String(), w.FormDataContentType()
+
+	symlist, err = BodyToSymlist(strings.NewReader(body), contentType, FormLimits{Hash: "md5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ = NewParser(strings.NewReader(`user == 'bob' && upload.filename # '\.php$' && upload.size == 16 && ` +
+		`upload.content_type == 'application/octet-stream' && upload.hash == md5('<?php echo 1; ?>') && ` +
+		`size(upload.files) == 2 && any(upload.files, it.filename == 'cat.jpg' && it.size == 4)`))
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+
+	limits := []FormLimits{{MaxBodySize: 100}, {MaxFileSize: 10}, {MaxFieldSize: 2}, {MaxParts: 2}, {Hash: "crc"}}
+	for _, l := range limits {
+		if _, err := MultipartToSymlist(strings.NewReader(body), contentType, l); err == nil {
+			t.Errorf("expect an error for limits %+v", l)
 		}
-		v := strings.Split(line, "%")
-		if len(v) != 3 {
-			continue
+	}
+	if _, err := MultipartToSymlist(strings.NewReader(body), contentType, DefaultFormLimits); err != nil {
+		t.Errorf("default limits: %v", err)
+	}
+	if _, err := BodyToSymlist(strings.NewReader("a=1"), "text/plain", DefaultFormLimits); err == nil {
+		t.Errorf("expect an error for text/plain")
+	}
+	symlist, err = BodyToSymlist(strings.NewReader(`{"a":1}`), "application/json; charset=utf-8", DefaultFormLimits)
+	if v, _ := SymbolLookup(symlist, "a"); err != nil || v == nil || v.Value.(int64) != 1 {
+		t.Errorf("expect a = 1, actual %v %v", v, err)
+	}
+}
+
+func TestXml(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <Order currency="EUR" id="7">
+      <customer>bob &amp; co</customer>
+      <price unit="cent">1000</price>
+      <item sku="a1">pen</item>
+      <item sku="b2"><qty>3</qty></item>
+      <tag>x</tag><tag>y</tag>
+    </Order>
+  </soap:Body>
+</soap:Envelope>`
+	symlist, err := XmlToSymlist(doc, DefaultXmlLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []string{
+		"Envelope.Body.Order@currency == 'EUR' && Envelope.Body.Order@id == '7'",
+		"Envelope.Body.Order.customer == 'bob & co'",
+		"Envelope.Body.Order.price == '1000' && Envelope.Body.Order.price@unit == 'cent'",
+		"size(Envelope.Body.Order.item) == 2 && any(Envelope.Body.Order.item, it == 'pen' && it@sku == 'a1')",
+		"any(Envelope.Body.Order.item, it@sku == 'b2' && it.qty == '3')",
+		"Envelope.Body.Order.tag @ ('z') || all(Envelope.Body.Order.tag, it # '^[xy]$')",
+		"Envelope.Body.Order.customer@('bob & co')",
+	}
+	expects := []int{1, 1, 1, 1, 1, 1, 1}
+	for i, rule := range rules {
+		h, err := NewParser(strings.NewReader(rule))
+		if err != nil {
+			t.Fatalf("%q: %v", rule, err)
+		}
+		if ret, err := h.Parse(symlist); ret != expects[i] {
+			t.Errorf("%q: expect %d, actual %d %v", rule, expects[i], ret, err)
+		}
+	}
+
+	// the same rule on the same data sent as JSON
+	h, _ := NewParser(strings.NewReader("Order@currency == 'EUR' && Order.id == '7'"), WithCoercion(NUMERIC))
+	x, _ := XmlToSymlist(`<Order currency="EUR"><id>7</id></Order>`, DefaultXmlLimits)
+	j, _ := JsonToSymlist(`{"Order":{"@currency":"EUR","id":7}}`)
+	for _, symlist := range []*SymList{x, j} {
+		if ret, err := h.Parse(symlist); ret != 1 {
+			t.Errorf("expect 1, actual %d %v", ret, err)
+		}
+	}
+
+	bad := []struct {
+		doc    string
+		limits XmlLimits
+	}{
+		{`<a><b><c/></b></a>`, XmlLimits{MaxDepth: 2}},
+		{`<a x="1"><b/></a>`, XmlLimits{MaxNodes: 2}},
+		{`<!DOCTYPE a [<!ENTITY e "x">]><a>&e;</a>`, XmlLimits{}},
+		{`<a>&e;</a>`, XmlLimits{}},
+		{`<a></b>`, XmlLimits{}},
+		{`<a/><b/>`, XmlLimits{}},
+		{``, XmlLimits{}},
+	}
+	for _, c := range bad {
+		if _, err := XmlToSymlist(c.doc, c.limits); err == nil {
+			t.Errorf("%q: expect an error", c.doc)
+		}
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read past the wanted fields")
+}
+
+func TestStreamJson(t *testing.T) {
+	rule := `let ua_lc = ua
+def bot = ua_lc # 'bot'
+bot || user.id == $ID || any(items, it.sku == 'x' && it > 1) || md5(sig) == 'x' => 1`
+	h, err := NewParser(strings.NewReader(rule), WithParams(map[string]interface{}{"ID": 7}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, selective := h.Variables()
+	if strings.Join(names, ",") != "items,sig,ua,user.id" || !selective {
+		t.Errorf("unexpected variables %v %v", names, selective)
+	}
+
+	body := `{"big":{"blob":"` + strings.Repeat("x", 1000) + `","list":[1,[2,{"a":3}]]},` +
+		`"user":{"name":"bob","id":7},"ua":"curl","items":[{"sku":"y"},{"sku":"x"}],"sig":"s"}`
+	symlist, err := h.DecodeJson(io.MultiReader(strings.NewReader(body), failingReader{}), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+	for p := symlist; p != nil; p = p.Next {
+		if strings.HasPrefix(p.Name, "big") || p.Name == "user.name" {
+			t.Errorf("unexpected symbol %s", p.Name)
+		}
+	}
+
+	// reading stops at the last wanted field, the broken tail is not read
+	symlist, err = JsonReaderToSymlist(io.MultiReader(strings.NewReader(`{"a":{"@b":"1","c":2},"d":`), failingReader{}),
+		[]string{"a@b", "a.c"}, 0)
+	if v, _ := SymbolLookup(symlist, "a@b"); err != nil || v == nil || v.Value.(string) != "1" {
+		t.Errorf("expect a@b = '1', actual %v %v", v, err)
+	}
+	if _, err = JsonReaderToSymlist(strings.NewReader(body), []string{"sig"}, 100); err == nil {
+		t.Errorf("expect an error for a body over 100 bytes")
+	}
+	if _, err = JsonReaderToSymlist(strings.NewReader(`[1]`), nil, 0); err == nil {
+		t.Errorf("expect an error for an array body")
+	}
+
+	// all symbols for count()
+	h, _ = NewParser(strings.NewReader("count() == 7"))
+	if _, selective := h.Variables(); selective {
+		t.Errorf("expect count() not selective")
+	}
+	symlist, _ = h.DecodeJson(strings.NewReader(body), 0)
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+}
+
+type testAddress struct {
+	City    string `json:"city"`
+	Country string `gohap:"country" json:"cc"`
+}
+
+type testBase struct {
+	ID    int64 `json:"id"`
+	Trace string
+}
+
+type testRequest struct {
+	testBase
+	Name    string            `json:"name"`
+	Secret  string            `json:"-"`
+	Note    string            `json:"note,omitempty"`
+	Age     uint8             `json:"age"`
+	Score   *float64          `json:"score"`
+	Missing *testAddress      `json:"missing"`
+	Address *testAddress      `json:"address"`
+	Tags    []string          `json:"tags"`
+	Items   []testAddress     `json:"items"`
+	Meta    map[string]string `json:"meta"`
+	Raw     []byte            `json:"raw"`
+	When    time.Time         `json:"when"`
+	Admin   bool              `json:"admin"`
+	hidden  string
+}
+
+func TestSymlistFromStruct(t *testing.T) {
+	score := 9.5
+	req := &testRequest{
+		testBase: testBase{ID: 42, Trace: "t1"},
+		Name:     "bob", Secret: "s", Age: 30, Score: &score,
+		Address: &testAddress{City: "Paris", Country: "FR"},
+		Tags:    []string{"a", "b"},
+		Items:   []testAddress{{City: "x", Country: "X"}, {City: "y"}},
+		Meta:    map[string]string{"k": "v"},
+		Raw:     []byte("raw"),
+		When:    time.Date(2014, 9, 13, 2, 30, 0, 0, time.UTC),
+		hidden:  "h",
+	}
+	symlist, err := SymlistFromStruct(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ := NewParser(strings.NewReader(`id == 42 && Trace == 't1' && name == 'bob' && age == 30 && score == 9.5 && ` +
+		`address.city == 'Paris' && address.country == 'FR' && size(tags) == 2 && any(items, it.country == 'X') && ` +
+		`meta.k == 'v' && raw == 'raw' && when == '2014-09-13T02:30:00Z'`))
+	if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+	for _, name := range []string{"Secret", "note", "missing.city", "admin", "hidden", "address.cc"} {
+		if _, err := SymbolLookup(symlist, name); err == nil {
+			t.Errorf("unexpected symbol %s", name)
+		}
+	}
+
+	// the same rule on the same data sent as JSON
+	buf, _ := json.Marshal(req)
+	j, _ := JsonToSymlist(string(buf))
+	h, _ = NewParser(strings.NewReader("id == 42 && address.city == 'Paris' && any(items, it.city == 'y')"))
+	for _, symlist := range []*SymList{symlist, j} {
+		if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+			t.Errorf("expect 1, actual %d %v", ret, err)
+		}
+	}
+
+	symlist, err = SymlistFromMap(map[string]interface{}{"a": map[string]interface{}{"b": 1}, "n": json.Number("2.5"),
+		"l": []interface{}{map[string]int{"k": 3}, 1, "x"}, "nil": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, _ = NewParser(strings.NewReader("a.b == 1 && n == 2.5 && size(l) == 3 && any(l, it.k == 3)"))
+	if ret, err := h.Parse(symlist); ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+
+	// items without value keep their place whichever way the data comes
+	type point struct {
+		V int `json:"v"`
+	}
+	type lists struct {
+		P []*point         `json:"p"`
+		B []bool           `json:"b"`
+		I []interface{}    `json:"i"`
+		E []struct{}       `json:"e"`
+		M []map[string]int `json:"m"`
+	}
+	data := &lists{[]*point{{1}, nil, {3}}, []bool{true, false}, []interface{}{1, nil, map[string]interface{}{}},
+		[]struct{}{{}}, []map[string]int{{}, {"k": 1}}}
+	s, err := SymlistFromStruct(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, _ = json.Marshal(data)
+	j, _ = JsonToSymlist(string(buf))
+	h, _ = NewParser(strings.NewReader("size(p) == 3 && size(b) == 2 && size(i) == 3 && size(e) == 1 && size(m) == 2 && any(i, it == 1)"))
+	for _, symlist := range []*SymList{s, j} {
+		if ret, err := h.Parse(symlist); err != nil || ret != 1 {
+			t.Errorf("expect 1, actual %d %v", ret, err)
+		}
+	}
+
+	if _, err := SymlistFromStruct(map[string]int{}); err == nil {
+		t.Errorf("expect an error for a map")
+	}
+	type node struct {
+		Next *node
+	}
+	loop := &node{}
+	loop.Next = loop
+	if _, err := SymlistFromStruct(loop); err == nil {
+		t.Errorf("expect an error for a cycle")
+	}
+}
+
+func BenchmarkSymlistFromStruct(b *testing.B) {
+	req := &testRequest{Name: "bob", Address: &testAddress{City: "Paris"}, Tags: []string{"a", "b"}}
+	for i := 0; i < b.N; i++ {
+		SymlistFromStruct(req)
+	}
+}
+
+func TestSymbolProvider(t *testing.T) {
+	calls := map[string]int{}
+	geo := ProviderFunc(func(name string) (*Factor, bool, error) {
+		calls[name] += 1
+		switch name {
+		case "geo.country":
+			f, _ := NewFactor(STRING, 0, "FR", "", nil)
+			return f, true, nil
+		case "reputation":
+			return nil, false, errors.New("reputation service down")
+		}
+		return nil, false, nil
+	})
+	request, _ := QueryToSymlist("ua=curl&geo.country=US")
+	symbols := ComposeProviders(request, geo)
+
+	h, _ := NewParser(strings.NewReader(`ua == 'curl' && geo.country == 'US' => 1; default => 0`))
+	if ret, err := h.Parse(symbols); err != nil || ret != 1 {
+		t.Errorf("expect the request first, actual %d %v", ret, err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("expect geo not asked, actual %v", calls)
+	}
+
+	h, _ = NewParser(strings.NewReader(`geo.country @ ('FR', 'DE') && geo.country != 'US' && len(geo.country) == 2 => 1; ` +
+		`ua == 'x' && reputation == 'bad' => 2; count() == 2 => 3; default => 0`))
+	if ret, err := h.Parse(ComposeProviders(geo, request)); err != nil || ret != 1 {
+		t.Errorf("expect 1, actual %d %v", ret, err)
+	}
+	if calls["geo.country"] != 1 {
+		t.Errorf("expect geo.country asked once, actual %d", calls["geo.country"])
+	}
+	if ret, err := h.Parse(ComposeProviders(geo, request)); err != nil || ret != 1 || calls["geo.country"] != 2 {
+		t.Errorf("expect geo.country asked again by another Parse, actual %d %v %v", ret, err, calls)
+	}
+
+	h, _ = NewParser(strings.NewReader(`reputation == 'bad' => 2; default => 0`))
+	if _, err := h.Parse(symbols); err == nil || !strings.Contains(err.Error(), "reputation service down") {
+		t.Errorf("expect the provider error, actual %v", err)
+	}
+	h, _ = NewParser(strings.NewReader(`missing == 'x' => 2; count() == 2 => 3; default => 0`))
+	if ret, err := h.Parse(symbols); ret != -1 || err == nil {
+		t.Errorf("expect a missing symbol error, actual %d %v", ret, err)
+	}
+	h, _ = NewParser(strings.NewReader(`count() == 2`))
+	if ret, err := h.Parse(symbols); err != nil || ret != 1 {
+		t.Errorf("expect count() 2, actual %d %v", ret, err)
+	}
+	var empty *SymList
+	if ret, err := h.Parse(empty); err != nil || ret != 0 {
+		t.Errorf("expect count() 0, actual %d %v", ret, err)
+	}
+
+	// repeated keys of a symlist composed ahead of the computing providers
+	tags, _ := QueryToSymlist("tag=a&tag=b&geo.country=US")
+	h, _ = NewParser(strings.NewReader(`size(tag) == 2 && any(tag, it == 'b') && all(tag, len(it) == 1) && tag == 'a'`))
+	for _, symbols := range []SymbolProvider{tags, ComposeProviders(tags, geo), ComposeProviders(nil, ComposeProviders(tags), geo)} {
+		if ret, err := h.Parse(symbols); err != nil || ret != 1 {
+			t.Errorf("expect the values of tag, actual %d %v", ret, err)
+		}
+	}
+	calls = map[string]int{}
+	h, _ = NewParser(strings.NewReader(`size(tag) == 1 && tag == 'a' && size(geo.country) == 1 && geo.country == 'FR'`))
+	if ret, err := h.Parse(ComposeProviders(geo, tags)); err != nil || ret != 1 || calls["tag"] != 1 || calls["geo.country"] != 1 {
+		t.Errorf("expect the first value behind a provider asked once, actual %d %v %v", ret, err, calls)
+	}
+}
+
+func TestUnicode(t *testing.T) {
+	cases := []struct {
+		opts   []Option
+		rule   string
+		expect int
+	}{
+		{nil, "len(name) == 4 && byte_len(name) == 12", 1},
+		{nil, "len('caf\xe9') == 4 && len(md5(name)) == 32", 1},
+		{nil, "cafe == 'café'", 0},
+		{[]Option{WithNormalization(NFC)}, "cafe == 'café' && cafe @ ('x', 'café')", 1},
+		{[]Option{WithNormalization(NFC)}, "cafe # '^café$' && cafe # pattern", 1},
+		{[]Option{WithNormalization(NFC)}, "len(cafe) == 5", 1},
+		{[]Option{WithNormalization(NFC)}, "wide == 'fi'", 0},
+		{[]Option{WithNormalization(NFKC)}, "wide == 'fi' && wide # '^fi$'", 1},
+		{nil, "upper == 'strasse'", 0},
+		{[]Option{WithCaseFolding(true)}, "upper == 'strasse' && upper != 'STRASS' && upper @ ('STRASSE')", 1},
+		{[]Option{WithCaseFolding(true)}, "sigma == 'σσ' && upper >= 'strasse' && upper <= 'strasse'", 1},
+		{[]Option{WithCaseFolding(true), WithNormalization(NFC)}, "upper_cafe == 'café'", 1},
+	}
+	input := `{"name":"张三丰人", "cafe":"cafe\u0301", "pattern":"^cafe\u0301$", "wide":"ｆｉ", ` +
+		`"upper":"STRAßE", "sigma":"Σς", "upper_cafe":"CAFÉ", "list":["a", "b"]}`
+	symlist, err := JsonToSymlist(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule), c.opts...)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if ret, err := h.Parse(symlist); err != nil || ret != c.expect {
+			t.Errorf("case %d: %q: expect %d, actual %d %v", i, c.rule, c.expect, ret, err)
+		}
+	}
+
+	for _, fn := range []string{"len", "byte_len"} {
+		h, err := NewParser(strings.NewReader(fn + "(list) == 2"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := fn + "() parameter should be 'string'"
+		if ret, err := h.Parse(symlist); err == nil || err.Error() != want {
+			t.Errorf("%s() of an array: expect error %q, actual %d %v", fn, want, ret, err)
+		}
+	}
+}
+
+func TestRuleFile(t *testing.T) {
+	specs, err := LoadRuleFile(os.DirFS("sample"), "waf.rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 3 {
+		t.Fatalf("%d rules, expect 3", len(specs))
+	}
+	if s := specs[0]; s.Id != "sqli-union" || s.Description != "union based SQL injection in the query" ||
+		s.Owner != "security" || s.Severity != "high" || !s.Enabled || len(s.Tests) != 2 || s.Line != 5 {
+		t.Errorf("sqli-union: %+v", s)
+	}
+	if s := specs[1]; s.Enabled || s.Tests[1].Expect != 0 || s.Tests[1].Line != 20 || !strings.HasPrefix(s.Rule, "// a token") {
+		t.Errorf("bad-token: %+v", s)
+	}
+	for _, spec := range specs {
+		for _, err := range spec.RunTests() {
+			t.Error(err)
 		}
-		values = append(values, v)
-		h, err := NewParser(strings.NewReader(v[1]))
+	}
+
+	malformed := []struct {
+		src  string
+		line int
+	}{
+		{"x == 1\n", 1},
+		{"// comment\n@owner a\n", 2},
+		{"@id a\n@expect yes x=1\nx == 1\n", 2},
+		{"@id a\n@enabled maybe\nx == 1\n", 2},
+		{"@id a\n@author b\nx == 1\n", 2},
+		{"@id a\n@expect 1 x=1\n\n@id b\nx == 1\n", 1},
+		{"@id a\nx == 1\n@id a\nx == 2\n", 3},
+		{"@id\nx == 1\n", 1},
+	}
+	for _, c := range malformed {
+		_, err := ParseRuleFile("bad.rules", strings.NewReader(c.src))
+		if e, ok := err.(*CompileError); !ok || e.File != "bad.rules" || e.Line != c.line {
+			t.Errorf("%q: %v, expect an error at line %d", c.src, err, c.line)
+		}
+	}
+
+	// compile errors are located in the file, failed cases reported each
+	specs, err = ParseRuleFile("t.rules", strings.NewReader(
+		"@id ok\n@expect 1 x=1\nx == '1'\n\n@id broken\n@expect 1\n\nx == 1 &&\n  y == == 2\n"+
+			"@id wrong\n@expect 1 x=2\n@expect 1 x=1\n@expect 1 {\"x\":\nx == '1'\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = specs[1].Compile()
+	if e, ok := err.(*CompileError); !ok || e.File != "t.rules" || e.Line != 9 {
+		t.Errorf("broken: %v, expect an error at t.rules line 9", err)
+	}
+	if errs := specs[1].RunTests(); len(errs) != 1 || errs[0].(*TestFailure).Err == nil {
+		t.Errorf("broken: %v", errs)
+	}
+	errs := specs[2].RunTests()
+	if len(errs) != 2 {
+		t.Fatalf("wrong: %v, expect 2 failures", errs)
+	}
+	if f := errs[0].(*TestFailure); f.Line != 11 || f.Expect != 1 || f.Actual != 0 || f.Err != nil {
+		t.Errorf("wrong: %v", f)
+	}
+	if f := errs[1].(*TestFailure); f.Line != 13 || f.Err == nil {
+		t.Errorf("wrong: %v", f)
+	}
+
+	// a comment ends the rule text of the last rule as well as of the others
+	for _, src := range []string{"@id tail\n@expect 1 x=1\nx == '1'\n// a note", "@id tail\n@expect 1 x=1\nx == '1' // a note\n\n@id b\nx == 2\n"} {
+		specs, err = ParseRuleFile("t.rules", strings.NewReader(src))
 		if err != nil {
 			t.Fatal(err)
 		}
-		handle = append(handle, h)
+		if !strings.HasSuffix(specs[0].Rule, "// a note\n") {
+			t.Errorf("%q: rule text %q", src, specs[0].Rule)
+		}
+		if _, err := specs[0].Compile(); err != nil {
+			t.Errorf("%q: %v", src, err)
+		}
+		if errs := specs[0].RunTests(); len(errs) != 0 {
+			t.Errorf("%q: %v", src, errs)
+		}
+	}
+}
 
+func TestWatcher(t *testing.T) {
+	good := "@id big\n@expect 1 {\"x\": 20}\n@expect 0 {\"x\": 5}\nx > 10\n"
+	fsys := fstest.MapFS{
+		"rules/a.rules": {Data: []byte(good), ModTime: time.Unix(1, 0)},
+		"rules/b.rules": {Data: []byte("@id off\n@enabled false\nx == == 1\n"), ModTime: time.Unix(1, 0)},
+		"rules/notes":   {Data: []byte("not a rule file")},
 	}
+	w, err := NewWatcher(fsys, "rules/*.rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloads := 0
+	w.OnReload(func(s *RuleSet) { reloads += 1 })
 
-	for i := 0; i < t.N; i++ {
-		index := rand.Intn(len(values))
-		v := values[index]
-		var symlist *SymList
-		if v[2][0] == '{' {
-			symlist, _ = JsonToSymlist(v[2])
-		} else {
-			symlist, _ = QueryToSymlist(v[2])
+	set := w.Rules()
+	if set.Version != 1 || len(set.Specs) != 1 || set.Parser("big") == nil || set.Parser("off") != nil {
+		t.Fatalf("%+v", set)
+	}
+	if err := w.poll(); err != nil || reloads != 0 {
+		t.Errorf("unchanged files: %v, %d reloads", err, reloads)
+	}
+
+	// a bad push keeps the last good rules, and is reported once
+	bad := []struct {
+		src    string
+		expect string
+	}{
+		{"@id big\nx > > 10\n", "rules/a.rules: line 2 column 5: syntax error"},
+		{"@id big\n@expect 1 {\"x\": 20}\nx > 100\n", "rules/a.rules: line 2: rule 'big': expect 1, actual 0"},
+		{"@id big\n@owner\n", "rules/a.rules: line 1 column 1: rule 'big' has no rule text"},
+		{good + "@id off\nx == 1\n", "rules/b.rules: line 1 column 1: rule 'off' already defined in rules/a.rules"},
+	}
+	for i, c := range bad {
+		fsys["rules/a.rules"] = &fstest.MapFile{Data: []byte(c.src), ModTime: time.Unix(int64(i+2), 0)}
+		err := w.poll()
+		if e, ok := err.(*ReloadError); !ok || !strings.HasPrefix(e.Error(), c.expect) {
+			t.Errorf("%q: %v, expect %s", c.src, err, c.expect)
+		}
+		if err := w.poll(); err != nil {
+			t.Errorf("%q: reported again: %v", c.src, err)
 		}
+		if w.Rules() != set {
+			t.Errorf("%q: rules replaced", c.src)
+		}
+	}
 
-		h := handle[index]
-		actual, err := h.Parse(symlist)
+	fsys["rules/a.rules"] = &fstest.MapFile{Data: []byte(good + "@id small\n@expect 1 {\"x\": 1}\nx < 2\n"), ModTime: time.Unix(9, 0)}
+	if err := w.poll(); err != nil {
+		t.Fatal(err)
+	}
+	if set := w.Rules(); set.Version != 2 || reloads != 1 || len(set.Parsers) != 2 || set.Parser("small") == nil {
+		t.Errorf("%+v, %d reloads", set, reloads)
+	}
+	delete(fsys, "rules/b.rules")
+	if err := w.Reload(); err != nil || w.Rules().Version != 3 {
+		t.Errorf("%v, version %d", err, w.Rules().Version)
+	}
+
+	fsys["rules/a.rules"] = &fstest.MapFile{Data: []byte("@id big\nx > > 10\n")}
+	if _, err := NewWatcher(fsys, "rules/*.rules"); err == nil {
+		t.Error("NewWatcher() of broken rules")
+	}
+	if _, err := NewWatcher(fsys, "rules/[.rules"); err == nil {
+		t.Error("NewWatcher() of a malformed pattern")
+	}
+
+	// the cases run with a store of their own
+	store := NewMemoryStore(0)
+	fsys["rules/a.rules"] = &fstest.MapFile{Data: []byte("@id hot\n@expect 0 ip=1\nrate(ip, 1, '1m')\n")}
+	if w, err = NewWatcher(fsys, "rules/*.rules", WithStateStore(store)); err != nil {
+		t.Fatal(err)
+	}
+	symlist, _ := QueryToSymlist("ip=1")
+	if ret, err := w.Rules().Parser("hot").Parse(symlist); ret != 0 || err != nil {
+		t.Errorf("first hit: %d %v", ret, err)
+	}
+}
+
+func TestWatcherImports(t *testing.T) {
+	fsys := fstest.MapFS{
+		"rules/a.rules":    {Data: []byte("@id a\n@expect 1 {\"x\": 20}\ninclude 'lib/limits.rules'\nx > limit\n"), ModTime: time.Unix(1, 0)},
+		"lib/limits.rules": {Data: []byte("let limit = 10"), ModTime: time.Unix(1, 0)},
+	}
+	w, err := NewWatcher(fsys, "rules/*.rules", WithResolver(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.poll(); err != nil || w.Rules().Version != 1 {
+		t.Errorf("unchanged files: %v, version %d", err, w.Rules().Version)
+	}
+
+	// the included file is outside the pattern, changing it reloads too
+	fsys["lib/limits.rules"] = &fstest.MapFile{Data: []byte("let limit = 30"), ModTime: time.Unix(2, 0)}
+	if err := w.poll(); err == nil || !strings.Contains(err.Error(), "expect 1, actual 0") {
+		t.Errorf("expect the failed case reported, actual %v", err)
+	}
+	if err := w.poll(); err != nil {
+		t.Errorf("reported again: %v", err)
+	}
+	fsys["lib/limits.rules"] = &fstest.MapFile{Data: []byte("let limit = 5"), ModTime: time.Unix(3, 0)}
+	if err := w.poll(); err != nil || w.Rules().Version != 2 {
+		t.Errorf("%v, version %d", err, w.Rules().Version)
+	}
+	delete(fsys, "lib/limits.rules")
+	if err := w.poll(); err == nil {
+		t.Error("expect the missing include reported")
+	}
+	fsys["lib/limits.rules"] = &fstest.MapFile{Data: []byte("let limit = 5"), ModTime: time.Unix(4, 0)}
+	if err := w.poll(); err != nil || w.Rules().Version != 3 {
+		t.Errorf("%v, version %d", err, w.Rules().Version)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	file := dir + "/a.rules"
+	if err := os.WriteFile(file, []byte("@id a\nx == '1'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWatcher(os.DirFS(dir), "*.rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded := make(chan *RuleSet, 1)
+	failed := make(chan error, 1)
+	w.OnReload(func(s *RuleSet) { reloaded <- s })
+	w.OnError(func(err error) { failed <- err })
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Watch(ctx, 5*time.Millisecond) }()
+
+	if err := os.WriteFile(file, []byte("@id a\nx == '1' ||\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-failed:
+		if _, ok := err.(*ReloadError); !ok {
+			t.Errorf("%T %v", err, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("bad rule not reported")
+	}
+	if err := os.WriteFile(file, []byte("@id b\nx == '2'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case s := <-reloaded:
+		if s.Version != 2 || s.Parser("b") == nil || w.Rules() != s {
+			t.Errorf("%+v", s)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload")
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Error(err)
+	}
+	if err := w.Watch(context.Background(), 0); err == nil {
+		t.Error("Watch() with interval 0")
+	}
+}
+
+func TestKeywordNames(t *testing.T) {
+	input := "size=10&hour=9&rate=1&match=a&all=b&any=c&none=d&as=e&let=f&def=g&now=h&unix=i&len=j&import=k&count=l"
+	cases := []struct {
+		rule   string
+		expect int
+	}{
+		{"size == '10'", 1},
+		{"hour == '9' && rate == '1' && match # '^a$'", 1},
+		{"all == 'b' && any == 'c' && none == 'd' && as == 'e'", 1},
+		{"let == 'f' && def == 'g' && import == 'k'", 1},
+		{"now == 'h' && unix == 'i' && len == 'j' && count == 'l'", 1},
+		{"len(size) == 2 && size(size) == 1 && any(size, it == '10')", 1},
+		{"size @ (hour, '10') && md5(size, hour) == md5('109')", 1},
+		{"let size = md5(hour); let any = '1'; size == md5('9') && any == '1'", 1},
+		{"def rate = hour == '9'; rate", 1},
+		{"def def = size == '10'; def", 1},
+		{"count() == 15", 1},
+	}
+	symlist, _ := QueryToSymlist(input)
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule))
 		if err != nil {
-			t.Error("line:", i+1, "Parse error:", err)
-		} else {
-			expect, _ := strconv.Atoi(v[0])
-			if actual != expect {
-				t.Errorf("line: %d expect %d, actual %d\n", i+1, expect, actual)
+			t.Errorf("case %d: %s: %v", i, c.rule, err)
+			continue
+		}
+		if actual, err := h.Parse(symlist); err != nil || actual != c.expect {
+			t.Errorf("case %d: %s: expect %d, actual %d %v", i, c.rule, c.expect, actual, err)
+		}
+	}
+
+	h, err := NewParser(strings.NewReader("size == '1' || rate(ip, 1, '1m') || all(x, it == any)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := h.Variables(); strings.Join(names, ",") != "any,ip,size,x" {
+		t.Errorf("variables %v", names)
+	}
+	// import 'file' as ns keeps its keywords
+	fsys := fstest.MapFS{"lib.rules": {Data: []byte("let as = 'e'")}}
+	h, err = NewParser(strings.NewReader("import 'lib.rules' as size\nsize.as == as"), WithResolver(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, err := h.Parse(symlist); err != nil || actual != 1 {
+		t.Errorf("import: %d %v", actual, err)
+	}
+
+	// '#i' run into a name is '#' before a name starting with i
+	symlist, _ = QueryToSymlist("x=abc&ip=^a&id=^A&p=B")
+	matches := map[string]int{"x #ip": 1, "x !#ip": 0, "x #id": 0, "x !#id": 1, "x #i p": 1, "x # p": 0,
+		"x #i'^A'": 1, "x !#i id": 0, "x #ip && x #i p": 1}
+	for rule, expect := range matches {
+		h, err := NewParser(strings.NewReader(rule))
+		if err != nil {
+			t.Errorf("%s: %v", rule, err)
+			continue
+		}
+		if actual, err := h.Parse(symlist); err != nil || actual != expect {
+			t.Errorf("%s: expect %d, actual %d %v", rule, expect, actual, err)
+		}
+	}
+}
+
+func TestMatchSyntax(t *testing.T) {
+	cases := []struct {
+		opts   []Option
+		rule   string
+		expect int // -1 for a compile error
+	}{
+		{nil, "match(x, 'a|ab') == 'ab'", 1},
+		{[]Option{WithRegexSyntax(PERL)}, "match(x, 'a|ab') == 'a'", 1},
+		{nil, "match(ua, 'Chrome/(?P<major>[0-9]+)', 'major') == '79'", 1},
+		{[]Option{WithRegexSyntax(PERL)}, "match(ua, 'Chrome/(?P<major>\\d+)', 'major') == '79'", 1},
+		{nil, "match(ua, 'Chrome/\\d+') == 'Chrome/79'", 1},
+		{nil, "match(ua, 'Chrome/(?P<major>[0-9]+)', 'minor') == ''", -1},
+		{nil, "atoi(match(ua, 'Firefox/([0-9]+)', 1)) == 0", 1},
+		{[]Option{WithRegexSyntax(PERL)}, "match(ua, '(\\d+)$', 1) == '79'", 1},
+		{nil, "match(cafe, 'caf.$') == ''", 1},
+		{[]Option{WithNormalization(NFC)}, "match(cafe, '^café$') == 'café'", 1},
+		{[]Option{WithNormalization(NFC)}, "match(cafe, 'caf(.)$', 1) == 'é'", 1},
+	}
+	symlist, _ := JsonToSymlist(`{"x":"ab", "ua":"Chrome/79", "cafe":"cafe\u0301"}`)
+	for i, c := range cases {
+		h, err := NewParser(strings.NewReader(c.rule), c.opts...)
+		if c.expect == -1 {
+			if _, ok := err.(*CompileError); !ok {
+				t.Errorf("case %d: %s: %v, expect a compile error", i, c.rule, err)
 			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %d: %s: %v", i, c.rule, err)
+			continue
+		}
+		if actual, err := h.Parse(symlist); err != nil || actual != c.expect {
+			t.Errorf("case %d: %s: expect %d, actual %d %v", i, c.rule, c.expect, actual, err)
 		}
 	}
 }
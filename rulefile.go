@@ -0,0 +1,254 @@
+package filter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+ * a rule of a rule file with its metadata and test cases. a rule file
+ * holds rules one after the other, each starts with its @id header:
+ *
+ *	// lines before the first rule are comments
+ *	@id          sqli-union
+ *	@description union based SQL injection in the query
+ *	@owner       security
+ *	@severity    high
+ *	@enabled     true
+ *	@expect 1    q=1+union+select+passwd
+ *	@expect 0    {"q": "union of workers"}
+ *	q #i 'union[[:space:]]+select'
+ *
+ * headers are lines starting with @name, the other lines up to the next
+ * @id are the rule text. @description may be given on several lines,
+ * they are joined with a space; @enabled defaults to true. '@expect value
+ * input' is a test case: the rule evaluated with input, a JSON object if
+ * it starts with '{' else a query string, returns value
+ */
+type RuleSpec struct {
+	Id          string
+	Description string
+	Owner       string
+	Severity    string
+	Enabled     bool
+	Rule        string
+	Tests       []*RuleTest
+	File        string
+	Line        int // of @id
+	ruleLine    int // of the first line of Rule
+}
+
+type RuleTest struct {
+	Expect int
+	Input  string
+	Line   int
+}
+
+var headerRegex = regexp.MustCompile(`^@([a-z]+)(?:[ \t]+(.*))?$`)
+
+/*
+ * read the rules of a rule file from fsys
+ */
+func LoadRuleFile(fsys fs.FS, name string) ([]*RuleSpec, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseRuleFile(name, f)
+}
+
+/*
+ * read the rules of a rule file from in, name is the file name errors
+ * are reported with. format errors are *CompileError, the rule texts are
+ * not compiled
+ */
+func ParseRuleFile(name string, in io.Reader) ([]*RuleSpec, error) {
+	specs := []*RuleSpec{}
+	ids := map[string]int{}
+	var spec *RuleSpec
+	var body []string // rule text of spec, header lines are blank
+	fail := func(line int, format string, a ...interface{}) error {
+		return &CompileError{name, line, 1, fmt.Sprintf(format, a...)}
+	}
+	finish := func() error {
+		if spec == nil {
+			return nil
+		}
+		for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+			body = body[:len(body)-1]
+		}
+		for len(body) > 0 && strings.TrimSpace(body[0]) == "" {
+			body = body[1:]
+			spec.ruleLine += 1
+		}
+		if len(body) == 0 {
+			return fail(spec.Line, "rule '%s' has no rule text", spec.Id)
+		}
+		// a '//' comment needs its newline to end, on the last line too
+		spec.Rule = strings.Join(body, "\n") + "\n"
+		specs = append(specs, spec)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(in)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		m := headerRegex.FindStringSubmatch(strings.TrimLeft(line, " \t"))
+		if m == nil {
+			if spec != nil {
+				body = append(body, line)
+			} else if s := strings.TrimSpace(line); s != "" && !strings.HasPrefix(s, "//") {
+				return nil, fail(n, "rule text before the first @id")
+			}
+			continue
+		}
+
+		header, value := m[1], strings.TrimSpace(m[2])
+		if header == "id" {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			if value == "" {
+				return nil, fail(n, "@id without id")
+			}
+			if first, ok := ids[value]; ok {
+				return nil, fail(n, "rule '%s' already defined at line %d", value, first)
+			}
+			ids[value] = n
+			spec = &RuleSpec{Id: value, Enabled: true, File: name, Line: n, ruleLine: n + 1}
+			body = nil
+			continue
+		}
+		if spec == nil {
+			return nil, fail(n, "@%s before the first @id", header)
+		}
+		// keeps the lines of the rule text where they are in the file
+		body = append(body, "")
+
+		switch header {
+		case "description":
+			if spec.Description != "" && value != "" {
+				spec.Description += " "
+			}
+			spec.Description += value
+		case "owner":
+			spec.Owner = value
+		case "severity":
+			spec.Severity = value
+		case "enabled":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fail(n, "@enabled '%s' is no boolean", value)
+			}
+			spec.Enabled = enabled
+		case "expect":
+			expect, input := value, ""
+			if i := strings.IndexAny(value, " \t"); i >= 0 {
+				expect, input = value[:i], strings.TrimSpace(value[i+1:])
+			}
+			v, err := strconv.Atoi(expect)
+			if err != nil {
+				return nil, fail(n, "@expect '%s' is no integer", expect)
+			}
+			spec.Tests = append(spec.Tests, &RuleTest{v, input, n})
+		default:
+			return nil, fail(n, "unknown header @%s", header)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+/*
+ * compile the rule text, compile errors are located in the rule file
+ */
+func (r *RuleSpec) Compile(opts ...Option) (*Parser, error) {
+	h, err := NewParser(strings.NewReader(r.Rule), opts...)
+	if err != nil {
+		if e, ok := err.(*CompileError); ok && e.File == "" {
+			if e.Line > 0 {
+				e.Line += r.ruleLine - 1
+			} else {
+				e.Line = r.Line
+			}
+			e.File = r.File
+		}
+		return nil, err
+	}
+	return h, nil
+}
+
+/*
+ * a test case whose rule returned another value than expected or failed
+ */
+type TestFailure struct {
+	Id     string
+	File   string
+	Line   int
+	Expect int
+	Actual int
+	Err    error // of compiling, input or evaluation, nil if the value differs
+}
+
+func (e *TestFailure) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: line %d: rule '%s': %v", e.File, e.Line, e.Id, e.Err)
+	}
+	return fmt.Sprintf("%s: line %d: rule '%s': expect %d, actual %d", e.File, e.Line, e.Id, e.Expect, e.Actual)
+}
+
+/*
+ * evaluate the test cases of the rule, disabled rules are tested too.
+ * the errors are *TestFailure, one per failed test case, or a single one
+ * if the rule doesn't compile
+ */
+func (r *RuleSpec) RunTests(opts ...Option) []error {
+	h, err := r.Compile(opts...)
+	if err != nil {
+		return []error{&TestFailure{r.Id, r.File, r.Line, 0, 0, err}}
+	}
+
+	failures := []error{}
+	for _, test := range r.Tests {
+		failure := &TestFailure{r.Id, r.File, test.Line, test.Expect, 0, nil}
+		symlist, err := testSymlist(test.Input)
+		if err == nil {
+			failure.Actual, err = h.Parse(symlist)
+		}
+		if err != nil {
+			failure.Err = err
+			failures = append(failures, failure)
+		} else if failure.Actual != test.Expect {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+/*
+ * symlist of a test input, a JSON object or a query string
+ */
+func testSymlist(input string) (*SymList, error) {
+	if input == "" {
+		return nil, nil
+	}
+	if input[0] == '{' {
+		return JsonToSymlist(input)
+	}
+	if !strings.Contains(input, "=") {
+		return nil, errors.New(fmt.Sprintf("input '%s' is neither JSON nor a query string", input))
+	}
+	return QueryToSymlist(input)
+}
@@ -0,0 +1,53 @@
+package filter_test
+
+import (
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	filter "github.com/soforth/gohap"
+	"github.com/soforth/gohap/gohaptest"
+)
+
+var samples = []string{"condition", "false", "function", "query", "true", "hash", "match", "regex", "glob", "contains", "array", "let", "number", "abnormal", "waf.rules"}
+
+func TestFilter(t *testing.T) {
+	gohaptest.Run(t, os.DirFS("sample"), samples)
+}
+
+func BenchmarkFilter(b *testing.B) {
+	rand.Seed(int64(time.Now().Second()))
+	cases, err := gohaptest.Load(os.DirFS("sample"), "bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	// pre-store Parser handles, the inputs are read in the loop
+	handles := make([]*filter.Parser, len(cases))
+	for i, c := range cases {
+		if handles[i], err = filter.NewParser(strings.NewReader(c.Rule)); err != nil {
+			b.Fatalf("%s:%d: %v", c.File, c.Line, err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index := rand.Intn(len(cases))
+		c := cases[index]
+		symlist, err := c.Symlist()
+		if err != nil {
+			b.Fatalf("%s:%d: %v", c.File, c.Line, err)
+		}
+		actual, err := handles[index].Parse(symlist)
+		if c.WantError {
+			if err == nil || !strings.Contains(err.Error(), c.ErrorText) {
+				b.Errorf("%s:%d: expect an error with '%s', actual %d %v", c.File, c.Line, c.ErrorText, actual, err)
+			}
+		} else if err != nil {
+			b.Errorf("%s:%d: %v", c.File, c.Line, err)
+		} else if actual != c.Expect {
+			b.Errorf("%s:%d: expect %d, actual %d", c.File, c.Line, c.Expect, actual)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package filter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+/*
+ * state shared between Parse calls, used by the rate() and
+ * count_distinct() builtins; implementations must be safe for
+ * concurrent use
+ */
+type StateStore interface {
+	// record a hit on key, return the number of hits within window
+	Hit(key string, window time.Duration, now time.Time) (float64, error)
+	// record member under key, return the number of distinct members
+	// seen within window
+	Distinct(key, member string, window time.Duration, now time.Time) (int, error)
+}
+
+/*
+ * in-memory StateStore: hits are counted with a sliding window counter
+ * (two fixed windows, the previous one weighted by its overlap), distinct
+ * members are kept with their last seen time; the least recently used
+ * keys are evicted once there are more than maxKeys of them
+ */
+type MemoryStore struct {
+	mutex      sync.Mutex
+	maxKeys    int
+	maxMembers int
+	lru        *list.List
+	keys       map[string]*list.Element
+}
+
+type stateEntry struct {
+	key     string
+	expires time.Time
+
+	// sliding window counter
+	start     time.Time
+	prev, cur float64
+
+	// distinct members and their last seen time
+	members map[string]time.Time
+}
+
+const (
+	defaultMaxKeys    = 100000
+	defaultMaxMembers = 1000
+)
+
+/*
+ * maxKeys <= 0 means the default (100000); each key keeps at most 1000
+ * distinct members, the oldest are dropped first
+ */
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	if maxKeys <= 0 {
+		maxKeys = defaultMaxKeys
+	}
+	s := new(MemoryStore)
+	s.maxKeys = maxKeys
+	s.maxMembers = defaultMaxMembers
+	s.lru = list.New()
+	s.keys = make(map[string]*list.Element)
+	return s
+}
+
+func (s *MemoryStore) Hit(key string, window time.Duration, now time.Time) (float64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e := s.entry(key, window, now)
+	elapsed := now.Sub(e.start)
+	if elapsed >= 2*window || elapsed < 0 {
+		e.start, e.prev, e.cur = now.Truncate(window), 0, 0
+	} else if elapsed >= window {
+		e.start, e.prev, e.cur = e.start.Add(window), e.cur, 0
+	}
+	e.cur += 1
+
+	weight := 1 - float64(now.Sub(e.start))/float64(window)
+	return e.prev*weight + e.cur, nil
+}
+
+func (s *MemoryStore) Distinct(key, member string, window time.Duration, now time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e := s.entry(key, window, now)
+	if e.members == nil {
+		e.members = make(map[string]time.Time)
+	}
+	for m, seen := range e.members {
+		if now.Sub(seen) >= window {
+			delete(e.members, m)
+		}
+	}
+	e.members[member] = now
+	if len(e.members) > s.maxMembers {
+		oldest, first := "", true
+		for m, seen := range e.members {
+			if first || seen.Before(e.members[oldest]) {
+				oldest, first = m, false
+			}
+		}
+		delete(e.members, oldest)
+	}
+	return len(e.members), nil
+}
+
+/*
+ * number of keys currently held
+ */
+func (s *MemoryStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.lru.Len()
+}
+
+/*
+ * find or create the entry of key and mark it as recently used, must be
+ * called with the mutex held
+ */
+func (s *MemoryStore) entry(key string, window time.Duration, now time.Time) *stateEntry {
+	var e *stateEntry
+	if elem, ok := s.keys[key]; ok {
+		s.lru.MoveToFront(elem)
+		e = elem.Value.(*stateEntry)
+		if now.After(e.expires) {
+			e.start, e.prev, e.cur, e.members = now.Truncate(window), 0, 0, nil
+		}
+	} else {
+		e = &stateEntry{key: key, start: now.Truncate(window)}
+		s.keys[key] = s.lru.PushFront(e)
+	}
+	e.expires = now.Add(2 * window)
+
+	// drop expired keys from the cold end, then enforce the key limit
+	for back := s.lru.Back(); back != nil && back != s.keys[key]; back = s.lru.Back() {
+		old := back.Value.(*stateEntry)
+		if !now.After(old.expires) && s.lru.Len() <= s.maxKeys {
+			break
+		}
+		s.lru.Remove(back)
+		delete(s.keys, old.key)
+	}
+	return e
+}
@@ -0,0 +1,197 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var errAllFound = errors.New("all symbols found")
+
+/*
+ * parse a JSON object read from r to symlist like JsonToSymlist(), keeping
+ * only the symbols named in names (all of them if names is nil). the
+ * object is read token by token: values of other keys are skipped without
+ * being kept, and reading stops once every name is found, so the rest of
+ * the body is neither read nor checked. a key given twice keeps its first
+ * value. maxSize limits the bytes read, 0 means unlimited
+ */
+func JsonReaderToSymlist(r io.Reader, names []string, maxSize int64) (*SymList, error) {
+	if maxSize > 0 {
+		r = &limitedReader{r, maxSize, maxSize, "body"}
+	}
+	s := &jsonStream{decoder: json.NewDecoder(r), found: map[string]bool{}}
+	s.decoder.UseNumber()
+	if names != nil {
+		s.names = map[string]bool{}
+		s.prefixes = map[string]bool{}
+		for _, name := range names {
+			s.names[name] = true
+			// the objects holding name: a for a.b, a and a.b for a.b@c
+			for i := 1; i < len(name); i++ {
+				if name[i] == '.' || name[i] == '@' {
+					s.prefixes[name[:i]] = true
+				}
+			}
+		}
+		if len(s.names) == 0 {
+			return nil, nil
+		}
+	}
+
+	token, err := s.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, errors.New("json body should be an object")
+	}
+	if err = s.object(""); err != nil && err != errAllFound {
+		return nil, err
+	}
+	return s.symlist, nil
+}
+
+/*
+ * parse a JSON object read from r, keeping the symbols the rule may look
+ * up (see Variables())
+ */
+func (h *Parser) DecodeJson(r io.Reader, maxSize int64) (*SymList, error) {
+	names, selective := h.Variables()
+	if !selective {
+		names = nil
+	}
+	return JsonReaderToSymlist(r, names, maxSize)
+}
+
+type jsonStream struct {
+	decoder  *json.Decoder
+	names    map[string]bool // nil for all
+	prefixes map[string]bool
+	found    map[string]bool
+	symlist  *SymList
+}
+
+/*
+ * the members of an object whose '{' is read, up to its '}'
+ */
+func (s *jsonStream) object(prefix string) error {
+	for s.decoder.More() {
+		token, err := s.decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return errors.New(fmt.Sprintf("json object key %v", token))
+		}
+		name := prefix + key
+		if strings.HasPrefix(key, "@") {
+			name = strings.TrimSuffix(prefix, ".") + key
+		}
+
+		wanted := s.names == nil || s.names[name]
+		if !wanted && !s.prefixes[name] {
+			if err := s.skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.value(name, wanted); err != nil {
+			return err
+		}
+	}
+	_, err := s.decoder.Token()
+	return err
+}
+
+/*
+ * the value of name, a symbol if wanted, else an object holding wanted
+ * symbols
+ */
+func (s *jsonStream) value(name string, wanted bool) error {
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		if v == '{' {
+			if err := s.object(name + "."); err != nil {
+				return err
+			}
+			break
+		}
+		if !wanted {
+			if err := s.skipNested(); err != nil {
+				return err
+			}
+			break
+		}
+		array := []interface{}{}
+		for s.decoder.More() {
+			var item interface{}
+			if err := s.decoder.Decode(&item); err != nil {
+				return err
+			}
+			array = append(array, item)
+		}
+		if _, err := s.decoder.Token(); err != nil {
+			return err
+		}
+		s.append(name, array)
+	case json.Number:
+		if wanted {
+			s.append(name, v)
+		}
+	case string:
+		if wanted {
+			s.append(name, v)
+		}
+	}
+
+	if s.names != nil && wanted && !s.found[name] {
+		s.found[name] = true
+		if len(s.found) == len(s.names) {
+			return errAllFound
+		}
+	}
+	return nil
+}
+
+func (s *jsonStream) append(name string, value interface{}) {
+	s.symlist = jsonToSymlist(s.symlist, "", map[string]interface{}{name: value})
+}
+
+func (s *jsonStream) skip() error {
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if _, ok := token.(json.Delim); ok {
+		return s.skipNested()
+	}
+	return nil
+}
+
+/*
+ * the rest of an object or array whose opening delimiter is read
+ */
+func (s *jsonStream) skipNested() error {
+	for depth := 1; depth > 0; {
+		token, err := s.decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth += 1
+		case json.Delim('}'), json.Delim(']'):
+			depth -= 1
+		}
+	}
+	return nil
+}
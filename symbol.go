@@ -39,6 +39,28 @@ func NewSymlistDouble(name string, value float64) (*SymList, error) {
 	return s, nil
 }
 
+func NewSymlistInteger(name string, value int64) (*SymList, error) {
+	s := new(SymList)
+	s.Kind = INTEGER
+	s.Name = name
+	s.Value = value
+	s.Next = nil
+	return s, nil
+}
+
+/*
+ * an array symbol, each item is a symlist of its own: scalar items are a
+ * single symbol named '' and object items hold their fields
+ */
+func NewSymlistArray(name string, items []*SymList) (*SymList, error) {
+	s := new(SymList)
+	s.Kind = ARRAY
+	s.Name = name
+	s.Value = items
+	s.Next = nil
+	return s, nil
+}
+
 func AppendSymlist(symlist *SymList, name, value string, kind FKind_t) (*SymList, error) {
 	pre := symlist
 	found := false
@@ -79,6 +101,60 @@ func AppendSymlistDouble(symlist *SymList, name string, value float64) (*SymList
 	return symlist, nil
 }
 
+func AppendSymlistInteger(symlist *SymList, name string, value int64) (*SymList, error) {
+	pre := symlist
+	found := false
+	for p := symlist; p != nil; p = p.Next {
+		if name == p.Name {
+			found = true
+			break
+		}
+		pre = p
+	}
+
+	if !found {
+		pre.Next, _ = NewSymlistInteger(name, value)
+	}
+
+	return symlist, nil
+}
+
+func AppendSymlistArray(symlist *SymList, name string, items []*SymList) (*SymList, error) {
+	pre := symlist
+	found := false
+	for p := symlist; p != nil; p = p.Next {
+		if name == p.Name {
+			found = true
+			break
+		}
+		pre = p
+	}
+
+	if !found {
+		pre.Next, _ = NewSymlistArray(name, items)
+	}
+
+	return symlist, nil
+}
+
+/*
+ * append a string value of name. a name given more than once keeps its
+ * first value, the later ones follow as symbols of the same name which
+ * only any(), all(), none() and size() see
+ */
+func appendSymlistValue(symlist *SymList, name, value string) *SymList {
+	s, _ := NewSymlistString(name, value)
+	if symlist == nil {
+		return s
+	}
+	pre := symlist
+	for pre.Next != nil {
+		pre = pre.Next
+	}
+	pre.Next = s
+	return symlist
+}
+
 func DeleteSymlist(symlist *SymList) {
 	pre := symlist
 	for p := symlist; p != nil; {
@@ -98,6 +174,18 @@ func SymbolLookup(symlist *SymList, name string) (*Factor, error) {
 				} else {
 					return NewFactor(DOUBLE, v, "", "", nil)
 				}
+			} else if p.Kind == INTEGER {
+				if v, err := cast2int64(p.Value); err != nil {
+					return nil, err
+				} else {
+					return NewIntegerFactor(v)
+				}
+			} else if p.Kind == ARRAY {
+				if v, ok := p.Value.([]*SymList); !ok {
+					return nil, errors.New(fmt.Sprintf("symbol '%s' has invalid array", name))
+				} else {
+					return NewArrayFactor(v)
+				}
 			} else {
 				if v, err := cast2string(p.Value); err != nil {
 					return nil, err
@@ -115,6 +203,10 @@ func DumpSymlist(symlist *SymList) {
 	for p := symlist; p != nil; p = p.Next {
 		if p.Kind == DOUBLE {
 			dump += fmt.Sprintf("%s\t%.2f\tDOUBLE\n", p.Name, p.Value)
+		} else if p.Kind == INTEGER {
+			dump += fmt.Sprintf("%s\t%d\tINTEGER\n", p.Name, p.Value)
+		} else if p.Kind == ARRAY {
+			dump += fmt.Sprintf("%s\t[%d]\tARRAY\n", p.Name, len(p.Value.([]*SymList)))
 		} else {
 			dump += fmt.Sprintf("%s\t'%s'\tSTRING\n", p.Name, p.Value)
 		}
@@ -125,6 +217,8 @@ func DumpSymlist(symlist *SymList) {
 /*
  * parse a query string to symlist_t struct , string format should be:
  * nmq=testmq&mac=xxxx&bootid=xxxx...
+ * a key given more than once (tag=a&tag=b) has its first value, any(),
+ * all(), none() and size() take all of its values
  */
 func QueryToSymlist(query string) (symlist *SymList, err error) {
 	var middle int = 0
@@ -138,18 +232,10 @@ func QueryToSymlist(query string) (symlist *SymList, err error) {
 
 		end = strings.IndexByte(data[middle:], '&')
 		if end == -1 {
-			if symlist == nil {
-				symlist, _ = NewSymlist(data[:middle], data[middle+1:], STRING)
-			} else {
-				symlist, _ = AppendSymlistString(symlist, data[:middle], data[middle+1:])
-			}
+			symlist = appendSymlistValue(symlist, data[:middle], data[middle+1:])
 			break
 		} else {
-			if symlist == nil {
-				symlist, _ = NewSymlist(data[:middle], data[middle+1:middle+end], STRING)
-			} else {
-				symlist, _ = AppendSymlistString(symlist, data[:middle], data[middle+1:middle+end])
-			}
+			symlist = appendSymlistValue(symlist, data[:middle], data[middle+1:middle+end])
 			data = data[middle+end+1:]
 		}
 	}
@@ -159,6 +245,12 @@ func QueryToSymlist(query string) (symlist *SymList, err error) {
 /*
  * parse a JSON string to symlist_t struct, string format should be:
  * {"double_name":10.0, "interger_name": 99, "string_name":"FIFA WC 2014", ...}
+ * nested objects are flattened with dotted names ({"a":{"b":1}} gives a.b),
+ * keys starting with '@' are attributes of their object ({"a":{"@b":1}}
+ * gives a@b, as XmlToSymlist() does), arrays become array symbols;
+ * integers that fit in int64 are integers, other numbers doubles;
+ * booleans and nulls are left out, in arrays they are empty array items
+ * so size() counts them
  */
 func JsonToSymlist(jstr string) (symlist *SymList, err error) {
 	jsroot, err := js.NewJson([]byte(jstr))
@@ -171,29 +263,77 @@ func JsonToSymlist(jstr string) (symlist *SymList, err error) {
 		return nil, err
 	}
 
+	return jsonToSymlist(nil, "", jsMap), nil
+}
+
+func jsonToSymlist(symlist *SymList, prefix string, jsMap map[string]interface{}) *SymList {
 	for k, v := range jsMap {
+		name := prefix + k
+		if strings.HasPrefix(k, "@") {
+			name = strings.TrimSuffix(prefix, ".") + k
+		}
 		switch u := v.(type) {
 		case json.Number:
-			dbl, err := v.(json.Number).Float64()
+			kind, value, err := jsonNumber(string(u))
 			if err != nil {
 				continue
 			}
-			if symlist == nil {
-				symlist, _ = NewSymlistDouble(k, dbl)
+			if kind == INTEGER && symlist == nil {
+				symlist, _ = NewSymlistInteger(name, value.(int64))
+			} else if kind == INTEGER {
+				symlist, _ = AppendSymlistInteger(symlist, name, value.(int64))
+			} else if symlist == nil {
+				symlist, _ = NewSymlistDouble(name, value.(float64))
 			} else {
-				symlist, _ = AppendSymlistDouble(symlist, k, dbl)
+				symlist, _ = AppendSymlistDouble(symlist, name, value.(float64))
 			}
 		case string:
 			if symlist == nil {
-				symlist, _ = NewSymlistString(k, u)
+				symlist, _ = NewSymlistString(name, u)
 			} else {
-				symlist, _ = AppendSymlistString(symlist, k, u)
+				symlist, _ = AppendSymlistString(symlist, name, u)
+			}
+		case map[string]interface{}:
+			symlist = jsonToSymlist(symlist, name+".", u)
+		case []interface{}:
+			if symlist == nil {
+				symlist, _ = NewSymlistArray(name, jsonToItems(u))
+			} else {
+				symlist, _ = AppendSymlistArray(symlist, name, jsonToItems(u))
 			}
 		default:
 			continue
 		}
-
 	}
+	return symlist
+}
 
-	return symlist, nil
+func jsonToItems(array []interface{}) []*SymList {
+	items := []*SymList{}
+	for _, v := range array {
+		var item *SymList
+		switch u := v.(type) {
+		case json.Number:
+			if kind, value, err := jsonNumber(string(u)); err != nil {
+				break
+			} else if kind == INTEGER {
+				item, _ = NewSymlistInteger("", value.(int64))
+			} else {
+				item, _ = NewSymlistDouble("", value.(float64))
+			}
+		case string:
+			item, _ = NewSymlistString("", u)
+		case map[string]interface{}:
+			item = jsonToSymlist(nil, "", u)
+		case []interface{}:
+			item, _ = NewSymlistArray("", jsonToItems(u))
+		}
+		if item == nil {
+			// booleans, nulls and objects without symbols keep their place
+			// as an empty array, which compares with no value
+			item, _ = NewSymlistArray("", []*SymList{})
+		}
+		items = append(items, item)
+	}
+	return items
 }
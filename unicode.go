@@ -0,0 +1,61 @@
+package filter
+
+import (
+	"fmt"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+type NKind_t int // for unicode normalisation
+
+const (
+	NFNONE = NKind_t(0) // strings as they are (default)
+	NFC    = NKind_t(1) // canonical composition: 'e' + U+0301 is 'é'
+	NFKC   = NKind_t(2) // compatibility composition as well: 'ｆｉ' is 'fi'
+)
+
+func nkind2str(kind NKind_t) string {
+	switch kind {
+	case NFNONE:
+		return "none"
+	case NFC:
+		return "nfc"
+	case NFKC:
+		return "nfkc"
+	}
+	return fmt.Sprintf("%d", int(kind))
+}
+
+/*
+ * s in the normal form set by WithNormalization()
+ */
+func normalize(kind NKind_t, s string) string {
+	switch kind {
+	case NFC:
+		return norm.NFC.String(s)
+	case NFKC:
+		return norm.NFKC.String(s)
+	}
+	return s
+}
+
+func (env *Env) normalize(s string) string {
+	if env.parser == nil {
+		return s
+	}
+	return normalize(env.parser.normalization, s)
+}
+
+/*
+ * s as string comparisons see it: normalised, and case folded if set by
+ * WithCaseFolding(). folding is the same in every locale ('ß' is 'ss',
+ * 'Σ' and 'ς' are 'σ')
+ */
+func (env *Env) comparable(s string) string {
+	s = env.normalize(s)
+	if env.parser != nil && env.parser.caseFolding {
+		s = cases.Fold().String(s)
+	}
+	return s
+}
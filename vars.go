@@ -0,0 +1,101 @@
+package filter
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+ * the names of the input symbols the rule may look up, sorted; names of
+ * declarations, $parameters and 'it' are left out, as are the fields of
+ * array items. the second result is false when the rule needs all of the
+ * input whatever the names, as count() does
+ */
+func (h *Parser) Variables() ([]string, bool) {
+	w := &varWalker{names: map[string]bool{}, seen: map[*Binding]bool{}, selective: true}
+	w.grammer(h.grammer, h.bindings)
+
+	names := []string{}
+	for name := range w.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, w.selective
+}
+
+type varWalker struct {
+	names     map[string]bool
+	seen      map[*Binding]bool
+	selective bool
+}
+
+func (w *varWalker) grammer(g *Grammer, scope map[string]*Binding) {
+	for ; g != nil; g = g.Grammer {
+		w.expr(g.Expr, scope, false)
+	}
+}
+
+func (w *varWalker) expr(e *Expr, scope map[string]*Binding, quant bool) {
+	for ; e != nil; e = e.Left {
+		w.term(e.Right, scope, quant)
+	}
+}
+
+func (w *varWalker) term(t *Term, scope map[string]*Binding, quant bool) {
+	if t == nil {
+		return
+	}
+	w.factor(t.Left, scope, quant)
+	switch v := t.Right.(type) {
+	case *Factor:
+		w.factor(v, scope, quant)
+	case *List:
+		w.list(v, scope, quant)
+	case *Expr:
+		w.expr(v, scope, quant || t.Kind == ANY || t.Kind == ALL || t.Kind == NONE)
+	}
+}
+
+func (w *varWalker) list(l *List, scope map[string]*Binding, quant bool) {
+	for ; l != nil; l = l.Next {
+		w.factor(l.Factor, scope, quant)
+	}
+}
+
+func (w *varWalker) factor(f *Factor, scope map[string]*Binding, quant bool) {
+	if f == nil {
+		return
+	}
+	switch v := f.Value.(type) {
+	case *Func:
+		if v.Kind == COUNT {
+			w.selective = false
+		}
+		w.list(v.List, scope, quant)
+	case *List:
+		w.list(v, scope, quant)
+	case string:
+		if f.Kind != VARIABLE || strings.HasPrefix(v, "$") {
+			return
+		}
+		if quant && (v == "it" || strings.HasPrefix(v, "it.") || strings.HasPrefix(v, "it@")) {
+			return
+		}
+		b, ok := scope[v]
+		if !ok {
+			w.names[v] = true
+			return
+		}
+		if w.seen[b] {
+			return
+		}
+		// declarations are evaluated outside of any quantifier
+		w.seen[b] = true
+		switch u := b.Value.(type) {
+		case *Factor:
+			w.factor(u, b.scope, false)
+		case *Expr:
+			w.expr(u, b.scope, false)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+ * the enabled rules of the rule files of a watcher, compiled, in the order
+ * of the files and of the rules in them. a rule set is not changed once
+ * loaded, a reload makes a new one
+ */
+type RuleSet struct {
+	Version int // 1 for the first set, counted up by each reload
+	Loaded  time.Time
+	Specs   []*RuleSpec
+	Parsers []*Parser // Parsers[i] is the compiled Specs[i]
+	byId    map[string]*Parser
+}
+
+/*
+ * the compiled rule id, nil if there is none
+ */
+func (s *RuleSet) Parser(id string) *Parser {
+	return s.byId[id]
+}
+
+/*
+ * error of a reload rejected because of one or more rules, the previous
+ * rule set stays active
+ */
+type ReloadError struct {
+	Errors []error
+}
+
+func (e *ReloadError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%v (and %d more errors)", e.Errors[0], len(e.Errors)-1)
+}
+
+/*
+ * a watcher keeps the rule files of fsys matching pattern (see fs.Glob(),
+ * as "rules/*.rules") compiled. the files, and the ones the rules import
+ * or include from the resolver of WithResolver(), are polled: when one is
+ * added, removed or its size or modification time changes, all of them
+ * are loaded again, the rules compiled with the options of the watcher
+ * and their @expect cases run (with a state store of their own). only if
+ * every enabled rule compiles and passes its cases the new rule set
+ * replaces the active one, else the last good set stays active; disabled
+ * rules are neither compiled nor tested, so setting '@enabled false'
+ * takes a broken rule out of service
+ */
+type Watcher struct {
+	fsys    fs.FS
+	pattern string
+	opts    []Option
+	active  atomic.Value // *RuleSet
+	mutex   sync.Mutex   // serializes reloads
+	stamp   string       // of the rule files last loaded, good or not
+	imports *trackedFS   // files opened by the resolver in the last load
+
+	onReload func(*RuleSet)
+	onError  func(error)
+}
+
+/*
+ * a watcher with the rules loaded now, an error if they can't be
+ */
+func NewWatcher(fsys fs.FS, pattern string, opts ...Option) (*Watcher, error) {
+	if _, err := fs.Glob(fsys, pattern); err != nil {
+		return nil, err
+	}
+	w := &Watcher{fsys: fsys, pattern: pattern, opts: opts}
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+/*
+ * f is called with the new rule set after each reload replacing the
+ * active one; set before Watch() is started
+ */
+func (w *Watcher) OnReload(f func(*RuleSet)) {
+	w.onReload = f
+}
+
+/*
+ * f is called with the error of each reload Watch() rejects (a
+ * *ReloadError when rules are wrong) or poll failing; set before Watch()
+ * is started
+ */
+func (w *Watcher) OnError(f func(error)) {
+	w.onError = f
+}
+
+/*
+ * the active rule set, safe to call from any goroutine
+ */
+func (w *Watcher) Rules() *RuleSet {
+	return w.active.Load().(*RuleSet)
+}
+
+/*
+ * poll the files every interval until ctx is done, the reloads happen in
+ * the calling goroutine, usually go w.Watch(ctx, time.Second)
+ */
+func (w *Watcher) Watch(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New(fmt.Sprintf("watch interval %v should be positive", interval))
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := w.poll(); err != nil && w.onError != nil {
+				w.onError(err)
+			}
+		}
+	}
+}
+
+/*
+ * reload if the files changed since they were last loaded; a failed
+ * version is reported once, not at each poll
+ */
+func (w *Watcher) poll() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	stamp, err := w.fileStamp()
+	if err != nil {
+		return err
+	}
+	if stamp == w.stamp+w.imports.stamp(false) {
+		return nil
+	}
+	return w.reload()
+}
+
+/*
+ * load the files now whether they changed or not, the error is that of
+ * the rejected reload
+ */
+func (w *Watcher) Reload() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.reload()
+}
+
+func (w *Watcher) reload() error {
+	stamp, err := w.ruleStamp()
+	if err != nil {
+		return err
+	}
+	// the imported files are stamped as they were read
+	w.stamp, w.imports = stamp, &trackedFS{files: map[string]string{}}
+	set, err := w.load()
+	if err != nil {
+		return err
+	}
+	if old, ok := w.active.Load().(*RuleSet); ok {
+		set.Version = old.Version + 1
+	}
+	w.active.Store(set)
+	if w.onReload != nil {
+		w.onReload(set)
+	}
+	return nil
+}
+
+/*
+ * names, sizes and modification times of the rule files and of the files
+ * imported by the last load
+ */
+func (w *Watcher) fileStamp() (string, error) {
+	stamp, err := w.ruleStamp()
+	if err != nil {
+		return "", err
+	}
+	return stamp + w.imports.stamp(true), nil
+}
+
+func (w *Watcher) ruleStamp() (string, error) {
+	names, err := fs.Glob(w.fsys, w.pattern)
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		info, err := fs.Stat(w.fsys, name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s %s\n", name, statLine(info))
+	}
+	return b.String(), nil
+}
+
+/*
+ * size and modification time of a file, '-' if there is none
+ */
+func statLine(info fs.FileInfo) string {
+	if info == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+}
+
+/*
+ * the resolver of the rules, recording each file opened with its stat
+ * line; files that can't be opened are recorded too, so adding them
+ * reloads the rules
+ */
+type trackedFS struct {
+	fsys  fs.FS
+	files map[string]string
+}
+
+func (t *trackedFS) Open(name string) (fs.File, error) {
+	f, err := t.fsys.Open(name)
+	var info fs.FileInfo
+	if err == nil {
+		info, _ = f.Stat()
+	}
+	t.files[name] = statLine(info)
+	return f, err
+}
+
+/*
+ * a line per recorded file, with its stat line as recorded or, if now,
+ * as it is now
+ */
+func (t *trackedFS) stamp(now bool) string {
+	if t == nil {
+		return ""
+	}
+	names := []string{}
+	for name := range t.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		line := t.files[name]
+		if now {
+			info, _ := fs.Stat(t.fsys, name)
+			line = statLine(info)
+		}
+		fmt.Fprintf(&b, "%s %s\n", name, line)
+	}
+	return b.String()
+}
+
+/*
+ * a rule set of the files, each error of a rule is collected
+ */
+func (w *Watcher) load() (*RuleSet, error) {
+	names, err := fs.Glob(w.fsys, w.pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	// the last option, so the resolver recorded is the one the rules use
+	opts := append(append([]Option{}, w.opts...), func(h *Parser) {
+		if h.resolver != nil {
+			w.imports.fsys = h.resolver
+			h.resolver = w.imports
+		}
+	})
+	set := &RuleSet{Version: 1, Loaded: time.Now(), byId: map[string]*Parser{}}
+	errs := []error{}
+	defined := map[string]*RuleSpec{}
+	for _, name := range names {
+		specs, err := LoadRuleFile(w.fsys, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, spec := range specs {
+			if first, ok := defined[spec.Id]; ok {
+				errs = append(errs, &CompileError{spec.File, spec.Line, 1,
+					fmt.Sprintf("rule '%s' already defined in %s line %d", spec.Id, first.File, first.Line)})
+				continue
+			}
+			defined[spec.Id] = spec
+			if !spec.Enabled {
+				continue
+			}
+			h, err := spec.Compile(opts...)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			// the cases run on their own parser and store, leaving the
+			// counters of rate() and count_distinct() of live rules alone
+			testOpts := append(append([]Option{}, opts...), WithStateStore(NewMemoryStore(0)))
+			if failures := spec.RunTests(testOpts...); len(failures) > 0 {
+				errs = append(errs, failures...)
+				continue
+			}
+			set.Specs = append(set.Specs, spec)
+			set.Parsers = append(set.Parsers, h)
+			set.byId[spec.Id] = h
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &ReloadError{errs}
+	}
+	return set, nil
+}
@@ -0,0 +1,149 @@
+package filter
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+ * limits of a decoded XML document, 0 means unlimited
+ */
+type XmlLimits struct {
+	MaxDepth int // nesting of elements
+	MaxNodes int // elements and attributes
+}
+
+var DefaultXmlLimits = XmlLimits{
+	MaxDepth: 64,
+	MaxNodes: 10000,
+}
+
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	text     strings.Builder
+	children []*xmlNode
+}
+
+/*
+ * parse an XML document to symlist, with the same symbol model as
+ * JsonToSymlist(): elements are flattened to dotted paths from the root
+ * element and attributes follow their element after '@', so
+ *	<Envelope><Body><Order currency="EUR"><id>7</id></Order></Body></Envelope>
+ * gives Envelope.Body.Order.id = '7' and Envelope.Body.Order@currency =
+ * 'EUR'. namespace prefixes are dropped and values are strings; the text
+ * of an element is its own symbol, elements repeated within their parent
+ * become an array of items (the text of an item is 'it', its attributes
+ * and children it@name and it.name). entities other than the predefined
+ * ones are never expanded, a DOCTYPE declaring any is an error
+ */
+func XmlToSymlist(xstr string, limits XmlLimits) (*SymList, error) {
+	root, err := parseXml(strings.NewReader(xstr), limits)
+	if err != nil {
+		return nil, err
+	}
+	return xmlToSymlist(nil, root.name, root), nil
+}
+
+func parseXml(r io.Reader, limits XmlLimits) (*xmlNode, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = true
+	var root *xmlNode
+	stack := []*xmlNode{}
+	nodes := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, errors.New(fmt.Sprintf("xml element '%s' after the root element", t.Name.Local))
+			}
+			if limits.MaxDepth > 0 && len(stack) >= limits.MaxDepth {
+				return nil, errors.New(fmt.Sprintf("xml deeper than %d elements", limits.MaxDepth))
+			}
+			node := &xmlNode{name: t.Name.Local}
+			for _, attr := range t.Attr {
+				if attr.Name.Space != "xmlns" && attr.Name.Local != "xmlns" {
+					node.attrs = append(node.attrs, attr)
+				}
+			}
+			nodes += 1 + len(node.attrs)
+			if limits.MaxNodes > 0 && nodes > limits.MaxNodes {
+				return nil, errors.New(fmt.Sprintf("xml has more than %d nodes", limits.MaxNodes))
+			}
+			if len(stack) == 0 {
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.Directive:
+			if strings.Contains(string(t), "<!ENTITY") {
+				return nil, errors.New("xml entity declarations not supported")
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("xml without root element")
+	}
+	return root, nil
+}
+
+/*
+ * the symbols of node at path, an empty path for the fields of an item
+ */
+func xmlToSymlist(symlist *SymList, path string, node *xmlNode) *SymList {
+	for _, attr := range node.attrs {
+		symlist = appendSymlistValue(symlist, path+"@"+attr.Name.Local, attr.Value)
+	}
+	text := strings.TrimSpace(node.text.String())
+	if text != "" || (len(node.attrs) == 0 && len(node.children) == 0) {
+		symlist = appendSymlistValue(symlist, path, text)
+	}
+
+	// children in order of their first appearance, repeated ones as arrays
+	names := []string{}
+	groups := map[string][]*xmlNode{}
+	for _, child := range node.children {
+		if _, ok := groups[child.name]; !ok {
+			names = append(names, child.name)
+		}
+		groups[child.name] = append(groups[child.name], child)
+	}
+	for _, name := range names {
+		child := name
+		if path != "" {
+			child = path + "." + name
+		}
+		if len(groups[name]) == 1 {
+			symlist = xmlToSymlist(symlist, child, groups[name][0])
+			continue
+		}
+		items := []*SymList{}
+		for _, n := range groups[name] {
+			items = append(items, xmlToSymlist(nil, "", n))
+		}
+		if symlist == nil {
+			symlist, _ = NewSymlistArray(child, items)
+		} else {
+			symlist, _ = AppendSymlistArray(symlist, child, items)
+		}
+	}
+	return symlist
+}